@@ -0,0 +1,371 @@
+package ds1302
+
+import (
+    "errors"
+    "time"
+)
+
+// Ошибки будильников планировщика.
+var (
+    ErrAlarmID      = errors.New("ds1302: alarm id 0 is reserved")
+    ErrAlarmInvalid = errors.New("ds1302: alarm trigger cannot be stored")
+    ErrAlarmUnknown = errors.New("ds1302: alarm not found")
+    ErrAlarmsFull   = errors.New("ds1302: alarms do not fit in RAM area")
+    ErrAlarmsStore  = errors.New("ds1302: alarm RAM area is not set")
+    // ErrAlarmsCorrupt возвращается LoadAlarms, если область не
+    // сохранялась (новый модуль, потеря питания) или повреждена.
+    ErrAlarmsCorrupt = errors.New("ds1302: stored alarms are missing or corrupt")
+    // ErrAlarmsVersion возвращается LoadAlarms для формата, который
+    // прошивка не знает (откат на старую версию).
+    ErrAlarmsVersion = errors.New("ds1302: stored alarms have unknown version")
+)
+
+// Формат области будильников: версия (1), длина записей в байтах (1),
+// записи и CRC-16 (big-endian) по всему предыдущему. Запись версии 2 -
+// номер будильника и слово big-endian: биты 15-14 - солнечное событие,
+// бит 13 - признак расширения, биты 12-0 - минута суток для фиксированного
+// времени или сдвиг в минутах со смещением alarmBias для солнечного
+// события. Расширение - еще два байта: дни недели (биты 0-6) с признаком
+// последнего дня месяца в бите 7 и правило месяца - неделя в битах 7-5
+// (alarmLastWeek - последняя) и день месяца в битах 4-0.
+//
+// Версия 1 хранила вместо длины число записей по три байта, а сдвиг
+// солнечного события в битах 13-0 со смещением 1<<13; LoadAlarms читает
+// ее, а сохранение всегда идет в текущей версии.
+const (
+    alarmVersion  = 2
+    alarmHeader   = 2
+    alarmCRC      = 2
+    alarmRecord   = 3
+    alarmExt      = 2
+    alarmExtBit   = 1 << 13
+    alarmBias     = 1 << 12
+    alarmLastDay  = 0x80
+    alarmLastWeek = 7
+
+    alarmV1Bias = 1 << 13
+
+    minutesPerDay = 24 * 60
+)
+
+// AlarmsSize возвращает размер области ОЗУ для n будильников без дней
+// недели и месяца; такие правила добавляют будильнику 2 байта. Весь
+// 31 байт ОЗУ вмещает 9 простых будильников.
+func AlarmsSize(n int) uint8 {
+    return uint8(alarmHeader + n*alarmRecord + alarmCRC)
+}
+
+// PersistAlarms задает область ОЗУ для будильников: size байт начиная
+// с addr (см. AlarmsSize). После этого AddAlarm и RemoveAlarm сохраняют
+// набор будильников, а LoadAlarms восстанавливает его при запуске.
+func (s *Scheduler) PersistAlarms(addr, size uint8) {
+    s.alarmAddr, s.alarmSize = addr, size
+}
+
+// AddAlarm добавляет будильник id (1-255) с триггером t и сохраняет
+// набор в ОЗУ. Будильник с тем же id заменяется. При срабатывании
+// вызывается OnAlarm: функцию нельзя сохранить в ОЗУ, поэтому прошивка
+// связывает номера будильников с действиями сама.
+//
+// Хранится время с точностью до минуты: сдвиг должен быть целым числом
+// минут (для солнечного события - не больше 68 часов), а у фиксированного
+// времени, ограниченного днями недели или месяца, не должен переносить
+// срабатывание через полночь, иначе возвращается ErrAlarmInvalid. Отложенный повтор (Snooze) в ОЗУ
+// не сохраняется.
+func (s *Scheduler) AddAlarm(id uint8, t Trigger) (*Job, error) {
+    if id == 0 {
+        return nil, ErrAlarmID
+    }
+    var rec [alarmRecord + alarmExt]byte
+    if encodeAlarm(rec[:], id, t) == 0 {
+        return nil, ErrAlarmInvalid
+    }
+    j := s.alarmJob(id, t)
+    jobs := make([]*Job, 0, len(s.jobs)+1)
+    replaced := false
+    for _, v := range s.jobs {
+        if v.ID == id {
+            v, replaced = j, true
+        }
+        jobs = append(jobs, v)
+    }
+    if !replaced {
+        jobs = append(jobs, j)
+    }
+    if err := s.saveAlarms(jobs); err != nil {
+        return nil, err
+    }
+    s.jobs = jobs
+    return j, nil
+}
+
+// RemoveAlarm удаляет будильник id и сохраняет набор в ОЗУ.
+func (s *Scheduler) RemoveAlarm(id uint8) error {
+    jobs := make([]*Job, 0, len(s.jobs))
+    for _, v := range s.jobs {
+        if v.ID != id {
+            jobs = append(jobs, v)
+        }
+    }
+    if len(jobs) == len(s.jobs) {
+        return ErrAlarmUnknown
+    }
+    if err := s.saveAlarms(jobs); err != nil {
+        return err
+    }
+    s.jobs = jobs
+    return nil
+}
+
+// LoadAlarms читает будильники из ОЗУ и заменяет ими будильники
+// планировщика; задания, добавленные Add, не затрагиваются. Вызывается
+// при запуске после PersistAlarms:
+//
+//     sch.PersistAlarms(8, ds1302.AlarmsSize(4))
+//     if err := sch.LoadAlarms(); err != nil {
+//         sch.AddAlarm(1, ds1302.Daily(7, 0)) // Будильники по умолчанию
+//     }
+//
+// При ошибке набор будильников не изменяется.
+func (s *Scheduler) LoadAlarms() error {
+    if s.alarmSize == 0 {
+        return ErrAlarmsStore
+    }
+    d := s.RTC
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    var buf [RAMSize]byte
+    raw := buf[:s.alarmSize]
+    if err := d.readRAMBytes(s.alarmAddr, raw); err != nil {
+        return err
+    }
+    if len(raw) < alarmHeader+alarmCRC {
+        return d.fail(ErrAlarmsCorrupt)
+    }
+    n := alarmHeader + int(raw[1])
+    if raw[0] == 1 {
+        n = alarmHeader + int(raw[1])*alarmRecord
+    }
+    if n+alarmCRC > len(raw) {
+        return d.fail(ErrAlarmsCorrupt)
+    }
+    if crc := crc16(raw[:n]); raw[n] != byte(crc>>8) || raw[n+1] != byte(crc) {
+        return d.fail(ErrAlarmsCorrupt)
+    }
+    if raw[0] == 0 || raw[0] > alarmVersion {
+        return d.fail(ErrAlarmsVersion)
+    }
+
+    jobs := make([]*Job, 0, len(s.jobs)+n/alarmRecord)
+    for _, v := range s.jobs {
+        if v.ID == 0 {
+            jobs = append(jobs, v)
+        }
+    }
+    for pos := alarmHeader; pos < n; {
+        id, t, size := decodeAlarm(raw[pos:n], raw[0])
+        if size == 0 || id == 0 {
+            return d.fail(ErrAlarmsCorrupt)
+        }
+        jobs = append(jobs, s.alarmJob(id, t))
+        pos += size
+    }
+    s.jobs = jobs
+    return nil
+}
+
+// alarmJob создает задание будильника id, вызывающее OnAlarm.
+func (s *Scheduler) alarmJob(id uint8, t Trigger) *Job {
+    return &Job{Trigger: t, ID: id, Fn: func(now time.Time) {
+        if s.OnAlarm != nil {
+            s.OnAlarm(id, now)
+        }
+    }}
+}
+
+// saveAlarms записывает будильники из jobs в ОЗУ.
+func (s *Scheduler) saveAlarms(jobs []*Job) error {
+    if s.alarmSize == 0 {
+        return ErrAlarmsStore
+    }
+    d := s.RTC
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    var buf [RAMSize + alarmRecord + alarmExt]byte
+    buf[0] = alarmVersion
+    n := alarmHeader
+    for _, j := range jobs {
+        if j.ID == 0 {
+            continue
+        }
+        n += encodeAlarm(buf[n:], j.ID, j.Trigger)
+        if n+alarmCRC > int(s.alarmSize) || n+alarmCRC > RAMSize {
+            return d.fail(ErrAlarmsFull)
+        }
+    }
+    buf[1] = uint8(n - alarmHeader)
+    crc := crc16(buf[:n])
+    buf[n], buf[n+1] = byte(crc>>8), byte(crc)
+    return d.writeRAMBytes(s.alarmAddr, buf[:n+alarmCRC])
+}
+
+// Snooze откладывает будильник id на d от текущего времени RTC: Poll
+// вызовет OnAlarm еще раз, когда повтор наступит. Ringing сбрасывается.
+func (s *Scheduler) Snooze(id uint8, d time.Duration) error {
+    j := s.Alarm(id)
+    if j == nil {
+        return ErrAlarmUnknown
+    }
+    now, err := s.RTC.now()
+    if err != nil {
+        return err
+    }
+    j.ringing, j.snooze = false, now.Add(d)
+    return nil
+}
+
+// Acknowledge подтверждает будильник id: сбрасывает Ringing и отменяет
+// отложенный повтор. Следующее срабатывание - по расписанию.
+func (s *Scheduler) Acknowledge(id uint8) error {
+    j := s.Alarm(id)
+    if j == nil {
+        return ErrAlarmUnknown
+    }
+    j.ringing, j.snooze = false, time.Time{}
+    return nil
+}
+
+// Alarms возвращает будильники планировщика (задания с номерами), например
+// восстановленные LoadAlarms.
+func (s *Scheduler) Alarms() []*Job {
+    var alarms []*Job
+    for _, j := range s.jobs {
+        if j.ID != 0 {
+            alarms = append(alarms, j)
+        }
+    }
+    return alarms
+}
+
+// Alarm возвращает будильник id или nil.
+func (s *Scheduler) Alarm(id uint8) *Job {
+    for _, j := range s.jobs {
+        if j.ID == id && id != 0 {
+            return j
+        }
+    }
+    return nil
+}
+
+// encodeAlarm записывает в buf запись будильника id и возвращает ее длину
+// или 0, если триггер нельзя сохранить. Фиксированное время хранится
+// минутой суток с учетом сдвига - для ежедневного триггера это тот же
+// момент. Если же триггер ограничен днями, сдвиг через полночь перенес бы
+// срабатывание на другой день, поэтому такой триггер не сохраняется.
+func encodeAlarm(buf []byte, id uint8, t Trigger) int {
+    if t.Offset%time.Minute != 0 || t.MonthDay < -1 || t.MonthDay > 31 || t.Week < -1 || t.Week > 5 {
+        return 0
+    }
+    off := int(t.Offset / time.Minute)
+    var w uint16
+    switch t.Solar {
+    case 0:
+        if t.Hour > 23 || t.Minute > 59 {
+            return 0
+        }
+        m := int(t.Hour)*60 + int(t.Minute) + off
+        if (m < 0 || m >= minutesPerDay) && !t.daily() {
+            return 0
+        }
+        m %= minutesPerDay
+        if m < 0 {
+            m += minutesPerDay
+        }
+        w = uint16(m)
+    case Sunrise, Sunset:
+        if off < -alarmBias || off >= alarmBias {
+            return 0
+        }
+        w = uint16(t.Solar)<<14 | uint16(off+alarmBias)
+    default:
+        return 0
+    }
+    buf[0] = id
+    if t.daily() {
+        buf[1], buf[2] = byte(w>>8), byte(w)
+        return alarmRecord
+    }
+    w |= alarmExtBit
+    buf[1], buf[2] = byte(w>>8), byte(w)
+    buf[3] = byte(t.Days) & 0x7F
+    if t.MonthDay < 0 {
+        buf[3] |= alarmLastDay
+    } else {
+        buf[4] = byte(t.MonthDay)
+    }
+    if t.Week < 0 {
+        buf[4] |= alarmLastWeek << 5
+    } else {
+        buf[4] |= byte(t.Week) << 5
+    }
+    return alarmRecord + alarmExt
+}
+
+// daily сообщает, что триггер срабатывает каждый день.
+func (t Trigger) daily() bool {
+    return t.Days == EveryDay && t.MonthDay == 0 && t.Week == 0
+}
+
+// decodeAlarm разбирает запись версии version в начале raw и возвращает
+// номер, триггер и длину записи (0 - запись повреждена).
+func decodeAlarm(raw []byte, version uint8) (uint8, Trigger, int) {
+    if len(raw) < alarmRecord {
+        return 0, Trigger{}, 0
+    }
+    id, w := raw[0], uint16(raw[1])<<8|uint16(raw[2])
+    ev := SolarEvent(w >> 14)
+    if version == 1 {
+        v := int(w & (1<<14 - 1))
+        if ev != 0 {
+            return id, At(ev, time.Duration(v-alarmV1Bias)*time.Minute), alarmRecord
+        }
+        if v >= minutesPerDay {
+            return 0, Trigger{}, 0
+        }
+        return id, Daily(uint8(v/60), uint8(v%60)), alarmRecord
+    }
+
+    var t Trigger
+    v := int(w & (alarmBias*2 - 1))
+    switch ev {
+    case 0:
+        if v >= minutesPerDay {
+            return 0, Trigger{}, 0
+        }
+        t = Daily(uint8(v/60), uint8(v%60))
+    case Sunrise, Sunset:
+        t = At(ev, time.Duration(v-alarmBias)*time.Minute)
+    default:
+        return 0, Trigger{}, 0
+    }
+    if w&alarmExtBit == 0 {
+        return id, t, alarmRecord
+    }
+    if len(raw) < alarmRecord+alarmExt {
+        return 0, Trigger{}, 0
+    }
+    t.Days = WeekdayMask(raw[3] & 0x7F)
+    t.MonthDay = int8(raw[4] & 0x1F)
+    if raw[3]&alarmLastDay != 0 {
+        t.MonthDay = -1
+    }
+    switch wk := raw[4] >> 5; wk {
+    case alarmLastWeek:
+        t.Week = -1
+    case 6:
+        return 0, Trigger{}, 0
+    default:
+        t.Week = int8(wk)
+    }
+    return id, t, alarmRecord + alarmExt
+}
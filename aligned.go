@@ -0,0 +1,73 @@
+package ds1302
+
+import (
+    "time"
+
+    "github.com/golangworker/ds1302-driver/bcd"
+    "github.com/golangworker/ds1302-driver/codec"
+)
+
+// SetTimeAligned устанавливает время по эталону ref, выровненное по границе секунды.
+//
+// ref - текущее время эталона с долями секунды (например, только что
+// полученное от NTP). SetTimeAligned дожидается начала следующей секунды
+// эталона и записывает ее в часы, так что новая секунда RTC начинается
+// одновременно с секундой эталона. Обычная SetTime отбрасывает доли
+// секунды и вносит систематическую ошибку до одной секунды.
+//
+// Ожидание длится до одной секунды. Если включена GuardBackwards,
+// перевод часов назад отклоняется с ErrTimeBackwards до ожидания.
+func (d *DS1302) SetTimeAligned(ref time.Time) error {
+    d.mu.Lock()
+    defer d.unlock()
+    return d.alignTo(ref, AdjustAligned, false)
+}
+
+// ForceSetTimeAligned выполняет SetTimeAligned без проверки GuardBackwards.
+func (d *DS1302) ForceSetTimeAligned(ref time.Time) error {
+    d.mu.Lock()
+    defer d.unlock()
+    return d.alignTo(ref, AdjustForced, true)
+}
+
+// alignTo дожидается начала следующей секунды эталона ref и записывает ее
+// в часы, отмечая в журнале источник src. force отключает GuardBackwards.
+func (d *DS1302) alignTo(ref time.Time, src AdjustSource, force bool) error {
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    start := time.Now()
+    next, err := d.clockTime(ref.Truncate(time.Second).Add(time.Second))
+    if err != nil {
+        return err
+    }
+    if !force {
+        if err := d.checkBackwards(next); err != nil {
+            return err
+        }
+    }
+    
+    // Все подготовительные обмены выполняются до ожидания: генератор
+    // останавливается и получает все поля, кроме секунд, чтобы после границы
+    // секунды осталась одна запись, которая запускает счет (см. writeClock).
+    prev, prevErr := d.auditPrior()
+    mode := codec.HourModeOf(d.readRegister(DS1302_HOURS_READ))
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    d.writeRegister(DS1302_SECONDS_WRITE, DS1302_CH_BIT)
+    d.writeDate(next, mode)
+    
+    time.Sleep(next.Sub(ref) - time.Since(start))
+    d.writeRegister(DS1302_SECONDS_WRITE, bcd.FromDec(uint8(next.Second())))
+    
+    // Прежнее время прочитано до ожидания: приводим его к моменту записи.
+    prev = prev.Add(next.Sub(ref).Round(time.Second))
+    d.recordAdjustment(prev, prevErr, next, src)
+    err = d.verifyTime(next)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    if err != nil {
+        return err
+    }
+    d.noteSet(next)
+    d.noteTimeSet(prev, prevErr, next, src)
+    return nil
+}
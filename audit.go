@@ -0,0 +1,282 @@
+package ds1302
+
+import (
+    "errors"
+    "time"
+)
+
+// ErrAuditDisabled возвращается Adjustments, если журнал не включен EnableAudit.
+var ErrAuditDisabled = errors.New("ds1302: audit log is not enabled")
+
+// AdjustmentSize - размер одной записи журнала перестановок в ОЗУ.
+// Журнал из n записей занимает 1 + n*AdjustmentSize байт.
+const AdjustmentSize = 8
+
+// AdjustSource - метка источника перестановки часов в журнале.
+// Значения от AdjustUser и выше приложение назначает само (см. SetTimeFrom).
+type AdjustSource uint8
+
+const (
+    AdjustUnknown AdjustSource = iota // Источник неизвестен
+    AdjustManual                      // SetTime, Tx.SetTime
+    AdjustForced                      // ForceSetTime, ForceSetTimeAligned, Tx.ForceSetTime
+    AdjustAligned                     // SetTimeAligned
+    AdjustSync                        // Перестановка часов Syncer
+    AdjustSlew                        // Плавная поправка Syncer через Slewer
+
+    AdjustUser AdjustSource = 0x80 // Первая метка, доступная приложению
+)
+
+// String возвращает название источника.
+func (s AdjustSource) String() string {
+    switch s {
+    case AdjustManual:
+        return "manual"
+    case AdjustForced:
+        return "forced"
+    case AdjustAligned:
+        return "aligned"
+    case AdjustSync:
+        return "sync"
+    case AdjustSlew:
+        return "slew"
+    }
+    if s >= AdjustUser {
+        return "user"
+    }
+    return "unknown"
+}
+
+// Adjustment - запись журнала перестановок часов.
+type Adjustment struct {
+    At     time.Time     // Новое время часов (для AdjustSlew - время эталона в момент решения)
+    Delta  time.Duration // Новое время минус прежнее, с точностью до секунды
+    Source AdjustSource  // Кто переставил часы
+
+    // PriorInvalid сообщает, что прежнее время было нечитаемым
+    // (недопустимые BCD значения), и Delta не определена.
+    PriorInvalid bool
+}
+
+// Формат журнала: байт заголовка, затем n записей по AdjustmentSize байт.
+// Заголовок: биты 7-5 - метка auditMagic, бит 4 - журнал заполнен,
+// биты 3-0 - индекс следующей записи. Запись: время Pack
+// (uint32 LE), Delta в секундах (int24 LE, насыщается) и метка источника.
+const (
+    auditMagic     = 0xA0
+    auditMagicMask = 0xE0
+    auditFull      = 0x10
+    auditNextMask  = 0x0F
+
+    deltaMax     = 1<<23 - 1
+    deltaUnknown = -1 << 23 // Прежнее время нечитаемо
+)
+
+// auditLog - расположение журнала в ОЗУ.
+type auditLog struct {
+    addr uint8
+    n    uint8
+}
+
+// EnableAudit включает журнал последних n перестановок часов в резервном ОЗУ
+// начиная с адреса addr. Журнал переживает перезагрузку МК, пока у DS1302
+// есть питание, поэтому EnableAudit вызывается при каждом запуске
+// с теми же параметрами; существующие записи сохраняются. n = 0 выключает
+// журнал, не трогая ОЗУ. В 31 байт помещается не больше трех записей.
+func (d *DS1302) EnableAudit(addr uint8, n int) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if n == 0 {
+        d.audit = nil
+        return nil
+    }
+    if n < 0 || n > auditNextMask || int(addr)+1+n*AdjustmentSize > RAMSize {
+        return d.fail(ErrRAMOutOfRange)
+    }
+
+    hdr, err := d.readRAM(addr)
+    if err != nil {
+        return err
+    }
+    if hdr&auditMagicMask != auditMagic || int(hdr&auditNextMask) >= n {
+        if err := d.writeRAMBytes(addr, []byte{auditMagic}); err != nil {
+            return err
+        }
+    }
+    d.audit = &auditLog{addr: addr, n: uint8(n)}
+    return nil
+}
+
+// Adjustments возвращает записи журнала перестановок, начиная с последней.
+func (d *DS1302) Adjustments() ([]Adjustment, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.adjustments()
+}
+
+func (d *DS1302) adjustments() ([]Adjustment, error) {
+    a := d.audit
+    if a == nil {
+        return nil, d.fail(ErrAuditDisabled)
+    }
+
+    var buf [RAMSize]byte
+    raw := buf[:1+int(a.n)*AdjustmentSize]
+    if err := d.readRAMBytes(a.addr, raw); err != nil {
+        return nil, err
+    }
+    hdr := raw[0]
+    if hdr&auditMagicMask != auditMagic {
+        return nil, nil
+    }
+
+    next := int(hdr & auditNextMask)
+    count := next
+    if hdr&auditFull != 0 {
+        count = int(a.n)
+    }
+    list := make([]Adjustment, 0, count)
+    for i := 1; i <= count; i++ {
+        j := (next - i + int(a.n)) % int(a.n)
+        list = append(list, decodeAdjustment(raw[1+j*AdjustmentSize:]))
+    }
+    return list, nil
+}
+
+// SetTimeFrom устанавливает время, как SetTime, и записывает в журнал
+// метку источника src (например, AdjustUser+1 для сервисного меню).
+func (d *DS1302) SetTimeFrom(t time.Time, src AdjustSource) error {
+    d.mu.Lock()
+    defer d.unlock()
+    return d.setTimeFrom(t, src)
+}
+
+func (d *DS1302) setTimeFrom(t time.Time, src AdjustSource) error {
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    t, err := d.clockTime(t)
+    if err != nil {
+        return err
+    }
+    if err := d.checkBackwards(t); err != nil {
+        return err
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    err = d.writeTimeAudited(t, src)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    return err
+}
+
+// writeTimeAudited записывает время и, если журнал включен, запись о перестановке,
+// затем при включенной Config.VerifyWrites проверяет запись. Время
+// приводится к UTC (см. clockTime).
+// Защита от записи должна быть снята вызывающим кодом.
+func (d *DS1302) writeTimeAudited(t time.Time, src AdjustSource) error {
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    t, err := d.clockTime(t)
+    if err != nil {
+        return err
+    }
+    prev, prevErr := d.auditPrior()
+    d.writeTime(t)
+    d.recordAdjustment(prev, prevErr, t, src)
+    if err := d.verifyTime(t); err != nil {
+        return err
+    }
+    d.noteSet(t)
+    d.noteTimeSet(prev, prevErr, t, src)
+    return nil
+}
+
+// auditPrior читает время перед перестановкой, если журнал включен
+// или есть подписчики OnTimeSet. Нечитаемое время не считается ошибкой
+// драйвера и не попадает в Metrics.
+func (d *DS1302) auditPrior() (time.Time, error) {
+    if d.audit == nil && len(d.onTimeSet) == 0 {
+        return time.Time{}, nil
+    }
+    return decodeTime(d.readTimeRegs())
+}
+
+// recordAdjustment добавляет в журнал перестановку с prev на t.
+// Защита от записи должна быть снята вызывающим кодом.
+func (d *DS1302) recordAdjustment(prev time.Time, prevErr error, t time.Time, src AdjustSource) {
+    var delta time.Duration
+    if prevErr == nil {
+        delta = t.Truncate(time.Second).Sub(prev)
+    }
+    d.logAdjustment(t, delta, prevErr != nil, src)
+}
+
+// logAdjustment записывает запись журнала, если он включен.
+// Защита от записи должна быть снята вызывающим кодом.
+func (d *DS1302) logAdjustment(at time.Time, delta time.Duration, priorInvalid bool, src AdjustSource) {
+    a := d.audit
+    if a == nil {
+        return
+    }
+    hdr := d.readRegister(DS1302_RAM_READ + a.addr*2)
+    next := hdr & auditNextMask
+    if hdr&auditMagicMask != auditMagic || next >= a.n {
+        hdr, next = auditMagic, 0
+    }
+
+    var rec [AdjustmentSize]byte
+    encodeAdjustment(rec[:], at, delta, priorInvalid, src)
+    d.writeRAM(a.addr+1+next*AdjustmentSize, rec[:])
+
+    next++
+    if next == a.n {
+        next = 0
+        hdr |= auditFull
+    }
+    d.writeRAM(a.addr, []byte{hdr&^auditNextMask | next})
+}
+
+// encodeAdjustment кодирует запись журнала в dst (AdjustmentSize байт).
+func encodeAdjustment(dst []byte, at time.Time, delta time.Duration, priorInvalid bool, src AdjustSource) {
+    secs := Pack(at)
+    d := int64(delta / time.Second)
+    switch {
+    case priorInvalid:
+        d = deltaUnknown
+    case d > deltaMax:
+        d = deltaMax
+    case d < -deltaMax:
+        d = -deltaMax
+    }
+    dst[0], dst[1], dst[2], dst[3] = byte(secs), byte(secs>>8), byte(secs>>16), byte(secs>>24)
+    dst[4], dst[5], dst[6] = byte(d), byte(d>>8), byte(d>>16)
+    dst[7] = byte(src)
+}
+
+// decodeAdjustment декодирует запись журнала из src.
+func decodeAdjustment(src []byte) Adjustment {
+    secs := uint32(src[0]) | uint32(src[1])<<8 | uint32(src[2])<<16 | uint32(src[3])<<24
+    d := int32(uint32(src[4])<<8|uint32(src[5])<<16|uint32(src[6])<<24) >> 8
+    a := Adjustment{
+        At:     Unpack(secs),
+        Source: AdjustSource(src[7]),
+    }
+    if d == deltaUnknown {
+        a.PriorInvalid = true
+    } else {
+        a.Delta = time.Duration(d) * time.Second
+    }
+    return a
+}
+
+// auditSlew записывает в журнал решение Syncer исправить часы плавно.
+// Отдельные шаги Slewer не записываются, чтобы не вытеснять из журнала
+// перестановки.
+func (d *DS1302) auditSlew(at time.Time, offset time.Duration) {
+    if d.audit == nil {
+        return
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    d.logAdjustment(at, offset.Round(time.Second), false, AdjustSlew)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+}
@@ -0,0 +1,83 @@
+// Package bcd содержит преобразования двоично-десятичного кода (BCD)
+// и 12/24-часового формата, общие для драйверов RTC, консоли и утилит хоста.
+//
+// В упакованном BCD байт хранит две десятичные цифры: старшую
+// в старшем полубайте и младшую в младшем (59 кодируется как 0x59).
+package bcd
+
+// ToDec преобразует упакованное BCD значение в десятичное.
+// Для недопустимых значений (см. Valid) результат не определен.
+func ToDec(b uint8) uint8 {
+    return toDec[b]
+}
+
+// FromDec преобразует десятичное значение 0-99 в упакованный BCD.
+// Для значений больше 99 результат не определен.
+func FromDec(d uint8) uint8 {
+    if int(d) < len(fromDec) {
+        return fromDec[d]
+    }
+    return (d/10)<<4 | d%10
+}
+
+// Таблицы преобразований. На Cortex-M0 нет аппаратного деления, и каждое
+// чтение времени - это семь преобразований; выборка из таблицы заменяет
+// вызовы программного деления. Таблицы заданы литералами, а не заполняются
+// при старте, чтобы TinyGo мог оставить их во флеш-памяти.
+var toDec = [256]uint8{
+    0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+    10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25,
+    20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35,
+    30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45,
+    40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53, 54, 55,
+    50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65,
+    60, 61, 62, 63, 64, 65, 66, 67, 68, 69, 70, 71, 72, 73, 74, 75,
+    70, 71, 72, 73, 74, 75, 76, 77, 78, 79, 80, 81, 82, 83, 84, 85,
+    80, 81, 82, 83, 84, 85, 86, 87, 88, 89, 90, 91, 92, 93, 94, 95,
+    90, 91, 92, 93, 94, 95, 96, 97, 98, 99, 100, 101, 102, 103, 104, 105,
+    100, 101, 102, 103, 104, 105, 106, 107, 108, 109, 110, 111, 112, 113, 114, 115,
+    110, 111, 112, 113, 114, 115, 116, 117, 118, 119, 120, 121, 122, 123, 124, 125,
+    120, 121, 122, 123, 124, 125, 126, 127, 128, 129, 130, 131, 132, 133, 134, 135,
+    130, 131, 132, 133, 134, 135, 136, 137, 138, 139, 140, 141, 142, 143, 144, 145,
+    140, 141, 142, 143, 144, 145, 146, 147, 148, 149, 150, 151, 152, 153, 154, 155,
+    150, 151, 152, 153, 154, 155, 156, 157, 158, 159, 160, 161, 162, 163, 164, 165,
+}
+
+var fromDec = [100]uint8{
+    0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09,
+    0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19,
+    0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29,
+    0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39,
+    0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+    0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59,
+    0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+    0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79,
+    0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+    0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99,
+}
+
+// Valid сообщает, что оба полубайта b являются десятичными цифрами.
+func Valid(b uint8) bool {
+    return b&0x0F <= 9 && b>>4 <= 9
+}
+
+// To12 преобразует час 0-23 в 12-часовой формат: час 1-12
+// и признак PM (полдень и позже).
+func To12(hour uint8) (h uint8, pm bool) {
+    pm = hour >= 12
+    h = hour % 12
+    if h == 0 {
+        h = 12
+    }
+    return h, pm
+}
+
+// To24 преобразует час 1-12 с признаком PM в 24-часовой формат (0-23).
+// 12 AM - полночь (0), 12 PM - полдень (12).
+func To24(hour uint8, pm bool) uint8 {
+    hour %= 12
+    if pm {
+        hour += 12
+    }
+    return hour
+}
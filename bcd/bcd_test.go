@@ -0,0 +1,120 @@
+package bcd
+
+import (
+    "testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+    for d := uint8(0); d <= 99; d++ {
+        b := FromDec(d)
+        if !Valid(b) {
+            t.Fatalf("FromDec(%d) = %#02x, not valid BCD", d, b)
+        }
+        if got := ToDec(b); got != d {
+            t.Fatalf("ToDec(FromDec(%d)) = %d", d, got)
+        }
+    }
+}
+
+func TestKnownValues(t *testing.T) {
+    tests := []struct {
+        dec uint8
+        bcd uint8
+    }{
+        {0, 0x00},
+        {9, 0x09},
+        {10, 0x10},
+        {59, 0x59},
+        {99, 0x99},
+    }
+    for _, tt := range tests {
+        if got := FromDec(tt.dec); got != tt.bcd {
+            t.Errorf("FromDec(%d) = %#02x, want %#02x", tt.dec, got, tt.bcd)
+        }
+        if got := ToDec(tt.bcd); got != tt.dec {
+            t.Errorf("ToDec(%#02x) = %d, want %d", tt.bcd, got, tt.dec)
+        }
+    }
+}
+
+func TestValid(t *testing.T) {
+    n := 0
+    for b := 0; b <= 0xFF; b++ {
+        want := b&0x0F <= 9 && b>>4 <= 9
+        if got := Valid(uint8(b)); got != want {
+            t.Errorf("Valid(%#02x) = %v, want %v", b, got, want)
+        }
+        if want {
+            n++
+        }
+    }
+    if n != 100 {
+        t.Errorf("Valid accepts %d values, want 100", n)
+    }
+}
+
+func TestTo12(t *testing.T) {
+    tests := []struct {
+        hour uint8
+        h    uint8
+        pm   bool
+    }{
+        {0, 12, false},
+        {1, 1, false},
+        {11, 11, false},
+        {12, 12, true},
+        {13, 1, true},
+        {23, 11, true},
+    }
+    for _, tt := range tests {
+        h, pm := To12(tt.hour)
+        if h != tt.h || pm != tt.pm {
+            t.Errorf("To12(%d) = %d, %v; want %d, %v", tt.hour, h, pm, tt.h, tt.pm)
+        }
+    }
+}
+
+func TestTo24(t *testing.T) {
+    tests := []struct {
+        h    uint8
+        pm   bool
+        hour uint8
+    }{
+        {12, false, 0},
+        {1, false, 1},
+        {11, false, 11},
+        {12, true, 12},
+        {1, true, 13},
+        {11, true, 23},
+    }
+    for _, tt := range tests {
+        if got := To24(tt.h, tt.pm); got != tt.hour {
+            t.Errorf("To24(%d, %v) = %d, want %d", tt.h, tt.pm, got, tt.hour)
+        }
+    }
+}
+
+func Test12RoundTrip(t *testing.T) {
+    for hour := uint8(0); hour < 24; hour++ {
+        h, pm := To12(hour)
+        if h < 1 || h > 12 {
+            t.Fatalf("To12(%d) = %d, out of 1-12", hour, h)
+        }
+        if got := To24(h, pm); got != hour {
+            t.Fatalf("To24(To12(%d)) = %d", hour, got)
+        }
+    }
+}
+
+func TestTables(t *testing.T) {
+    for b := 0; b < 256; b++ {
+        if got, want := ToDec(uint8(b)), uint8(b>>4*10+b&0x0F); got != want {
+            t.Errorf("ToDec(%#02x) = %d, want %d", b, got, want)
+        }
+    }
+    for d := 0; d < 256; d++ {
+        if got, want := FromDec(uint8(d)), uint8(d/10<<4|d%10); got != want {
+            t.Errorf("FromDec(%d) = %#02x, want %#02x", d, got, want)
+        }
+    }
+}
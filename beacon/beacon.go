@@ -0,0 +1,194 @@
+// Package beacon кодирует и принимает компактные подписанные кадры времени
+// для синхронизации DS1302 по радио без интернета (LoRa, ESP-NOW):
+// шлюз с точным временем (GPS, NTP) периодически рассылает кадр, а узлы
+// сети подстраивают по нему свои часы через ds1302.Syncer.
+//
+// Кадр (FrameSize байт): признак 'T', версия, номер шлюза (2), секунды
+// от 2000-01-01 UTC (4), доля секунды в 1/65536 (2), два резервных
+// нулевых байта и первые 8 байт
+// HMAC-SHA256 по предыдущим полям с общим ключом сети. Многобайтовые
+// поля - big-endian.
+package beacon
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/binary"
+    "errors"
+    "time"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+)
+
+// FrameSize - размер кадра времени в байтах.
+const FrameSize = 20
+
+// Поля кадра.
+const (
+    frameMagic   = 'T'
+    frameVersion = 1
+    macOffset    = 12
+    macSize      = FrameSize - macOffset
+)
+
+// epoch - начало отсчета секунд в кадре, как у DS1302.
+var epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+    // ErrFrame возвращается для данных, не являющихся кадром времени.
+    ErrFrame = errors.New("beacon: not a time beacon frame")
+    // ErrSignature возвращается, если подпись кадра не сходится с ключом.
+    ErrSignature = errors.New("beacon: bad frame signature")
+    // ErrReplay возвращается для кадра не новее уже принятого.
+    ErrReplay = errors.New("beacon: replayed or out-of-order frame")
+    // ErrNoBeacon возвращается Receiver.Now, пока не принято ни одного кадра.
+    ErrNoBeacon = errors.New("beacon: no frame received")
+    // ErrStale возвращается Receiver.Now, если последний кадр старше MaxAge.
+    ErrStale = errors.New("beacon: last frame is too old")
+)
+
+// Frame - содержимое кадра времени.
+type Frame struct {
+    Gateway uint16    // Номер шлюза
+    Time    time.Time // Время шлюза в момент передачи
+}
+
+// Encode записывает кадр f, подписанный ключом key, в начало buf
+// (не короче FrameSize) и возвращает его.
+func Encode(buf []byte, f Frame, key []byte) []byte {
+    frame := buf[:FrameSize]
+    d := f.Time.Sub(epoch)
+    frame[0], frame[1] = frameMagic, frameVersion
+    binary.BigEndian.PutUint16(frame[2:], f.Gateway)
+    binary.BigEndian.PutUint32(frame[4:], uint32(d/time.Second))
+    binary.BigEndian.PutUint16(frame[8:], uint16((d%time.Second)<<16/time.Second))
+    frame[10], frame[11] = 0, 0
+    copy(frame[macOffset:], sign(frame[:macOffset], key))
+    return frame
+}
+
+// Decode проверяет подпись кадра data ключом key и разбирает его.
+func Decode(data []byte, key []byte) (Frame, error) {
+    if len(data) != FrameSize || data[0] != frameMagic || data[1] != frameVersion {
+        return Frame{}, ErrFrame
+    }
+    if !hmac.Equal(data[macOffset:], sign(data[:macOffset], key)) {
+        return Frame{}, ErrSignature
+    }
+    secs := time.Duration(binary.BigEndian.Uint32(data[4:])) * time.Second
+    frac := time.Duration(binary.BigEndian.Uint16(data[8:])) * time.Second >> 16
+    return Frame{
+        Gateway: binary.BigEndian.Uint16(data[2:]),
+        Time:    epoch.Add(secs + frac),
+    }, nil
+}
+
+// sign возвращает подпись полей кадра.
+func sign(fields, key []byte) []byte {
+    mac := hmac.New(sha256.New, key)
+    mac.Write(fields)
+    return mac.Sum(nil)[:macSize]
+}
+
+// DefaultMaxAge - наибольший возраст кадра для Receiver.Now по умолчанию.
+const DefaultMaxAge = 10 * time.Second
+
+// StateSize - размер записи Receiver в резервном ОЗУ: время последнего
+// принятого кадра (секунды и доля) со служебными байтами ds1302.StoreAt.
+const StateSize = 6 + ds1302.StoreOverhead
+
+// state - время последнего принятого кадра в полях кадра.
+type state struct {
+    Secs uint32
+    Frac uint16
+}
+
+// Receiver принимает кадры времени и служит источником ds1302.TimeSource:
+//
+//     rx := &beacon.Receiver{Key: key, Latency: 40 * time.Millisecond, RTC: rtc, Addr: 20}
+//     syncer := ds1302.Syncer{RTC: rtc, Source: rx, Interval: time.Hour}
+//     ...
+//     if err := rx.Receive(packet); err == nil {
+//         syncer.SyncIfDue()
+//     }
+//
+// Время источника - время кадра плюс Latency (время в эфире и обработка
+// на шлюзе) плюс время, прошедшее с приема по таймеру МК; кадр старше
+// MaxAge не используется, чтобы ошибка таймера МК не попадала в RTC.
+//
+// Без RTC защита от повтора живет только в памяти МК: после перезагрузки
+// узла принимается любой когда-либо записанный кадр с верной подписью,
+// и Syncer записал бы в часы его старое время. Поэтому на узлах задайте RTC
+// и Addr - время последнего принятого кадра будет храниться в резервном
+// ОЗУ DS1302 (StateSize байт):
+//
+//     rx := &beacon.Receiver{Key: key, RTC: rtc, Addr: 20}
+type Receiver struct {
+    Key     []byte        // Общий ключ сети
+    Latency time.Duration // Задержка от передачи до вызова Receive
+    MaxAge  time.Duration // Наибольший возраст кадра (0 - DefaultMaxAge)
+    Gateway uint16        // Номер шлюза, чьи кадры принимаются (0 - любого)
+
+    RTC  *ds1302.DS1302 // Часы, в ОЗУ которых хранится последний принятый кадр (nil - не хранить)
+    Addr uint8          // Адрес записи в ОЗУ RTC
+
+    last     Frame
+    received time.Time // Момент приема last по таймеру МК
+    floor    state     // Последний принятый кадр, в том числе до перезагрузки
+    loaded   bool      // floor прочитан из ОЗУ
+}
+
+// Receive проверяет и запоминает кадр data. Кадры чужих шлюзов,
+// с неверной подписью и не новее принятого ранее (повтор записанного
+// кадра) отклоняются. Если задан RTC, принятый кадр сохраняется в ОЗУ;
+// ошибка записи возвращается, хотя кадр уже принят.
+func (r *Receiver) Receive(data []byte) error {
+    now := time.Now()
+    f, err := Decode(data, r.Key)
+    if err != nil {
+        return err
+    }
+    if r.Gateway != 0 && f.Gateway != r.Gateway {
+        return ErrFrame
+    }
+    if r.RTC != nil && !r.loaded {
+        // Нет записи (новый модуль, потеря питания) - нечего и повторять.
+        if r.RTC.LoadAt(r.Addr, &r.floor) != nil {
+            r.floor = state{}
+        }
+        r.loaded = true
+    }
+    st := state{
+        Secs: binary.BigEndian.Uint32(data[4:]),
+        Frac: binary.BigEndian.Uint16(data[8:]),
+    }
+    if st.Secs < r.floor.Secs || st.Secs == r.floor.Secs && st.Frac <= r.floor.Frac {
+        return ErrReplay
+    }
+    r.last, r.received, r.floor = f, now, st
+    if r.RTC != nil {
+        return r.RTC.StoreAt(r.Addr, &r.floor)
+    }
+    return nil
+}
+
+// Last возвращает последний принятый кадр.
+func (r *Receiver) Last() (Frame, bool) {
+    return r.last, !r.received.IsZero()
+}
+
+// Now возвращает время шлюза по последнему кадру.
+func (r *Receiver) Now() (time.Time, error) {
+    if r.received.IsZero() {
+        return time.Time{}, ErrNoBeacon
+    }
+    maxAge := r.MaxAge
+    if maxAge == 0 {
+        maxAge = DefaultMaxAge
+    }
+    age := time.Since(r.received)
+    if age > maxAge {
+        return time.Time{}, ErrStale
+    }
+    return r.last.Time.Add(r.Latency + age), nil
+}
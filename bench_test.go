@@ -0,0 +1,62 @@
+package ds1302_test
+
+import (
+    "testing"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+)
+
+// Бенчмарки на модели микросхемы без пауз между фронтами измеряют
+// накладные расходы драйвера: число обменов и работу с линиями. Время
+// на железе измеряет прошивка examples/bench; с DS1302_BRIDGE бенчмарки
+// идут через мост (см. newTestRTC) и показывают скорость моста.
+
+func newBenchRTC(b *testing.B) *ds1302.DS1302 {
+    b.Helper()
+    rtc, _, _ := newTestRTC(b, ds1302.Hour24)
+    if err := rtc.SetTime(date(2025, 7, 15, 10, 29, 59)); err != nil {
+        b.Fatal(err)
+    }
+    return rtc
+}
+
+// reportBus добавляет к результату число байт на шине за операцию.
+func reportBus(b *testing.B, rtc *ds1302.DS1302) {
+    b.ReportMetric(float64(rtc.Counters().Bytes)/float64(b.N), "bus-bytes/op")
+}
+
+func BenchmarkReadTime(b *testing.B) {
+    rtc := newBenchRTC(b)
+    rtc.ResetCounters()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        rtc.ReadTime()
+    }
+    reportBus(b, rtc)
+}
+
+func BenchmarkSetTime(b *testing.B) {
+    rtc := newBenchRTC(b)
+    t := date(2025, 7, 15, 10, 29, 59)
+    rtc.ResetCounters()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        if err := rtc.SetTime(t); err != nil {
+            b.Fatal(err)
+        }
+    }
+    reportBus(b, rtc)
+}
+
+func BenchmarkReadRAMBytes(b *testing.B) {
+    rtc := newBenchRTC(b)
+    var buf [ds1302.RAMSize]byte
+    rtc.ResetCounters()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        if err := rtc.ReadRAMBytes(0, buf[:]); err != nil {
+            b.Fatal(err)
+        }
+    }
+    reportBus(b, rtc)
+}
@@ -0,0 +1,56 @@
+package ds1302
+
+import (
+    "encoding/binary"
+)
+
+// BootCounterSize - число байт ОЗУ, занимаемых BootCounter.
+const BootCounterSize = 4
+
+// BootCounter - счетчик запусков устройства в резервном ОЗУ DS1302.
+// Значение хранится в 4 байтах (little-endian) начиная с выбранного адреса
+// и сохраняется между перезагрузками и глубоким сном, пока есть питание
+// от батареи.
+type BootCounter struct {
+    dev  *DS1302
+    addr uint8
+}
+
+// NewBootCounter создает счетчик запусков в ОЗУ по адресу addr.
+func NewBootCounter(d *DS1302, addr uint8) *BootCounter {
+    return &BootCounter{dev: d, addr: addr}
+}
+
+// Count возвращает текущее значение счетчика.
+func (c *BootCounter) Count() (uint32, error) {
+    c.dev.mu.Lock()
+    defer c.dev.mu.Unlock()
+    return c.count()
+}
+
+func (c *BootCounter) count() (uint32, error) {
+    var buf [BootCounterSize]byte
+    if err := c.dev.readRAMBytes(c.addr, buf[:]); err != nil {
+        return 0, err
+    }
+    return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+// Increment увеличивает счетчик на единицу и возвращает новое значение.
+func (c *BootCounter) Increment() (uint32, error) {
+    c.dev.mu.Lock()
+    defer c.dev.mu.Unlock()
+    n, err := c.count()
+    if err != nil {
+        return 0, err
+    }
+    n++
+    var buf [BootCounterSize]byte
+    binary.LittleEndian.PutUint32(buf[:], n)
+    return n, c.dev.writeRAMBytes(c.addr, buf[:])
+}
+
+// Reset обнуляет счетчик.
+func (c *BootCounter) Reset() error {
+    return c.dev.WriteRAMBytes(c.addr, make([]byte, BootCounterSize))
+}
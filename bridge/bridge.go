@@ -0,0 +1,104 @@
+// Package bridge реализует протокол "моста шины": прошивка на отладочной
+// плате с подключенной DS1302 выполняет команды линий CLK, DAT и RST,
+// приходящие по USB-serial, а хост получает ds1302.Pin, за которыми стоит
+// настоящая микросхема. Так go test на рабочей станции проверяет драйвер
+// на железе без прошивки тестовой логики под каждый тест.
+//
+// Каждая команда - один байт: номер операции в битах 7-2 и номер линии
+// в битах 1-0 (см. Op). Ответ дают только OpGet (байт 0 или 1 - уровень
+// линии) и Hello (Magic, Version). Неизвестные команды пропускаются.
+//
+// На стороне прошивки байты из UART передаются в Handler.Feed:
+//
+//     h := bridge.Handler{CLK: ds1302.PinOf(machine.GP10), DAT: ds1302.PinOf(machine.GP11), RST: ds1302.PinOf(machine.GP12)}
+//     for machine.Serial.Buffered() > 0 {
+//         b, _ := machine.Serial.ReadByte()
+//         h.Feed(b, machine.Serial)
+//     }
+//
+// На хосте Dial проверяет, что на порту отвечает мост, а Remote.Pins
+// возвращает линии для ds1302.New:
+//
+//     remote, err := bridge.Dial(port)
+//     rtc := ds1302.New(remote.Pins())
+//     rtc.Init()
+package bridge
+
+import (
+    "io"
+
+    "github.com/golangworker/ds1302-driver"
+)
+
+// Линии моста.
+const (
+    LineCLK = 0
+    LineDAT = 1
+    LineRST = 2
+)
+
+// Операции над линией.
+const (
+    OpLow    = 0 // Низкий уровень
+    OpHigh   = 1 // Высокий уровень
+    OpOutput = 2 // Режим выхода
+    OpInput  = 3 // Режим входа
+    OpGet    = 4 // Прочитать уровень; ответ - байт 0 или 1
+)
+
+// Hello - команда проверки связи; мост отвечает байтами Magic и Version.
+const Hello = 0xFF
+
+// Ответ на Hello.
+const (
+    Magic   = 'B'
+    Version = 1
+)
+
+// Op возвращает байт команды op над линией line.
+func Op(op, line uint8) byte {
+    return op<<2 | line&0x03
+}
+
+// Handler выполняет команды моста на стороне прошивки.
+type Handler struct {
+    CLK, DAT, RST ds1302.Pin
+}
+
+// Feed выполняет очередной байт команды из UART и, если команда требует
+// ответа, записывает его в w.
+func (h *Handler) Feed(b byte, w io.Writer) error {
+    if b == Hello {
+        _, err := w.Write([]byte{Magic, Version})
+        return err
+    }
+    var p ds1302.Pin
+    switch b & 0x03 {
+    case LineCLK:
+        p = h.CLK
+    case LineDAT:
+        p = h.DAT
+    case LineRST:
+        p = h.RST
+    default:
+        return nil
+    }
+    switch b >> 2 {
+    case OpLow:
+        p.Low()
+    case OpHigh:
+        p.High()
+    case OpOutput:
+        p.ConfigureOutput()
+    case OpInput:
+        p.ConfigureInput()
+    case OpGet:
+        level := byte(0)
+        if p.Get() {
+            level = 1
+        }
+        _, err := w.Write([]byte{level})
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,143 @@
+package bridge_test
+
+import (
+    "io"
+    "os"
+    "testing"
+    "time"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+    "github.com/golangworker/ds1302-driver/bridge"
+    "github.com/golangworker/ds1302-driver/sim"
+)
+
+// port соединяет хостовую сторону моста с прошивкой через пару каналов.
+type port struct {
+    io.Reader
+    io.Writer
+}
+
+// loopback запускает Handler поверх модели микросхемы и возвращает
+// хостовую сторону, как если бы плата была подключена по USB.
+func loopback(t *testing.T, chip *sim.Chip) io.ReadWriter {
+    t.Helper()
+    toDev, fromHost := io.Pipe()
+    fromDev, toHost := io.Pipe()
+    t.Cleanup(func() {
+        fromHost.Close()
+        toHost.Close()
+    })
+    clk, dat, rst := chip.Pins()
+    h := bridge.Handler{CLK: clk, DAT: dat, RST: rst}
+    go func() {
+        var b [1]byte
+        for {
+            if _, err := toDev.Read(b[:]); err != nil {
+                return
+            }
+            if err := h.Feed(b[0], toHost); err != nil {
+                return
+            }
+        }
+    }()
+    return port{fromDev, fromHost}
+}
+
+func TestLoopback(t *testing.T) {
+    now := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+    chip := sim.New()
+    chip.Now = func() time.Time { return now }
+    remote, err := bridge.Dial(loopback(t, chip))
+    if err != nil {
+        t.Fatalf("Dial: %v", err)
+    }
+    rtc := ds1302.New(remote.Pins())
+    rtc.Configure(ds1302.Config{DelayFn: func(uint32) {}})
+    rtc.Init()
+
+    want := time.Date(2031, 2, 3, 4, 5, 6, 0, time.UTC)
+    if err := rtc.SetTime(want); err != nil {
+        t.Fatalf("SetTime: %v", err)
+    }
+    if got := rtc.ReadTime(); !got.Equal(want) {
+        t.Errorf("ReadTime = %v, want %v", got, want)
+    }
+    data := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+    if err := rtc.WriteRAMBytes(3, data); err != nil {
+        t.Fatalf("WriteRAMBytes: %v", err)
+    }
+    if ram := chip.RAM(); string(ram[3:7]) != string(data) {
+        t.Errorf("chip RAM = % x, want % x", ram[3:7], data)
+    }
+    if err := remote.Err(); err != nil {
+        t.Errorf("Err = %v", err)
+    }
+}
+
+func TestDialNoBridge(t *testing.T) {
+    // Устройство, которое на любой байт отвечает эхом, - не мост.
+    toDev, fromHost := io.Pipe()
+    fromDev, toHost := io.Pipe()
+    defer fromHost.Close()
+    defer toHost.Close()
+    go io.Copy(toHost, toDev)
+    go func() {
+        for i := 0; i < 100; i++ {
+            fromHost.Write([]byte{bridge.Hello})
+        }
+    }()
+    if _, err := bridge.Dial(port{fromDev, fromHost}); err != bridge.ErrNoBridge {
+        t.Fatalf("Dial error = %v, want ErrNoBridge", err)
+    }
+}
+
+// TestHardware проверяет драйвер на настоящей микросхеме. Путь к порту
+// платы с прошивкой examples/bridge задается переменной DS1302_BRIDGE;
+// порт должен быть переведен в "сырой" режим:
+//
+//     stty -F /dev/ttyACM0 raw -echo
+//     DS1302_BRIDGE=/dev/ttyACM0 go test ./bridge
+func TestHardware(t *testing.T) {
+    path := os.Getenv("DS1302_BRIDGE")
+    if path == "" {
+        t.Skip("DS1302_BRIDGE is not set")
+    }
+    f, err := os.OpenFile(path, os.O_RDWR, 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+    remote, err := bridge.Dial(f)
+    if err != nil {
+        t.Fatalf("Dial(%s): %v", path, err)
+    }
+    rtc := ds1302.New(remote.Pins())
+    rtc.Init()
+
+    var saved [ds1302.RAMSize]byte
+    if err := rtc.ReadRAMBytes(0, saved[:]); err != nil {
+        t.Fatalf("ReadRAMBytes: %v", err)
+    }
+    report, err := rtc.Probe()
+    if err != nil {
+        t.Fatalf("Probe: %v", err)
+    }
+    if !report.OK() {
+        t.Errorf("Probe: %v", report)
+    }
+    if err := rtc.WriteRAMBytes(0, saved[:]); err != nil {
+        t.Errorf("restoring RAM: %v", err)
+    }
+
+    want := time.Now().UTC().Truncate(time.Second)
+    if err := rtc.ForceSetTime(want); err != nil {
+        t.Fatalf("ForceSetTime: %v", err)
+    }
+    got := rtc.ReadTime()
+    if d := got.Sub(want); d < 0 || d > 2*time.Second {
+        t.Errorf("ReadTime = %v right after setting %v", got, want)
+    }
+    if err := remote.Err(); err != nil {
+        t.Errorf("bridge I/O: %v", err)
+    }
+}
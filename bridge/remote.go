@@ -0,0 +1,118 @@
+package bridge
+
+import (
+    "errors"
+    "io"
+    "sync"
+
+    "github.com/golangworker/ds1302-driver"
+)
+
+// ErrNoBridge возвращается Dial, если на порту не отвечает мост этой версии.
+var ErrNoBridge = errors.New("bridge: no bus bridge on the port")
+
+// maxBatch - наибольшее число команд, отправляемых одной записью:
+// меньше приемного буфера UART прошивки.
+const maxBatch = 64
+
+// helloScan - сколько байт Dial просматривает в поисках ответа на Hello:
+// во входном буфере порта могут остаться ответы прерванного сеанса.
+const helloScan = 64
+
+// Remote - хостовая сторона моста. Команды, не требующие ответа, копятся
+// и уходят одной записью перед чтением линии и в конце каждого обмена
+// (спад RST), поэтому обмен с микросхемой стоит по одному обращению
+// к порту на прочитанный бит, а не на каждый фронт.
+//
+// Ошибки ввода-вывода запоминаются (см. Err): Pin не возвращает ошибок,
+// и после сбоя линии читаются нулями.
+type Remote struct {
+    rw  io.ReadWriter
+    mu  sync.Mutex
+    out []byte
+    err error
+}
+
+// Dial проверяет, что на rw отвечает мост, и возвращает Remote.
+// rw - открытый последовательный порт в "сыром" режиме; чтобы не зависнуть
+// на отключенной плате, задайте порту таймаут чтения.
+func Dial(rw io.ReadWriter) (*Remote, error) {
+    if _, err := rw.Write([]byte{Hello}); err != nil {
+        return nil, err
+    }
+    var b [1]byte
+    prev := byte(0)
+    for i := 0; i < helloScan; i++ {
+        if _, err := io.ReadFull(rw, b[:]); err != nil {
+            return nil, err
+        }
+        if prev == Magic && b[0] == Version {
+            return &Remote{rw: rw, out: make([]byte, 0, maxBatch)}, nil
+        }
+        prev = b[0]
+    }
+    return nil, ErrNoBridge
+}
+
+// Pins возвращает линии CLK, DAT и RST в порядке аргументов ds1302.New.
+func (r *Remote) Pins() (clk, dat, rst ds1302.Pin) {
+    return &pin{r, LineCLK}, &pin{r, LineDAT}, &pin{r, LineRST}
+}
+
+// Err возвращает первую ошибку ввода-вывода с момента прошлого вызова.
+func (r *Remote) Err() error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    err := r.err
+    r.err = nil
+    return err
+}
+
+// send добавляет команду в очередь; flush отправляет очередь сразу.
+func (r *Remote) send(op byte, flush bool) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.out = append(r.out, op)
+    if flush || len(r.out) >= maxBatch {
+        r.flush()
+    }
+}
+
+// flush отправляет накопленные команды. Вызывается под r.mu.
+func (r *Remote) flush() {
+    if len(r.out) == 0 {
+        return
+    }
+    if _, err := r.rw.Write(r.out); err != nil && r.err == nil {
+        r.err = err
+    }
+    r.out = r.out[:0]
+}
+
+// get отправляет очередь вместе с OpGet и ждет уровень линии.
+func (r *Remote) get(line uint8) bool {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.out = append(r.out, Op(OpGet, line))
+    r.flush()
+    var b [1]byte
+    if _, err := io.ReadFull(r.rw, b[:]); err != nil {
+        if r.err == nil {
+            r.err = err
+        }
+        return false
+    }
+    return b[0] != 0
+}
+
+// pin - линия моста, удовлетворяющая ds1302.Pin.
+type pin struct {
+    r    *Remote
+    line uint8
+}
+
+func (p *pin) ConfigureOutput() { p.r.send(Op(OpOutput, p.line), false) }
+func (p *pin) ConfigureInput()  { p.r.send(Op(OpInput, p.line), false) }
+func (p *pin) High()            { p.r.send(Op(OpHigh, p.line), false) }
+func (p *pin) Low()             { p.r.send(Op(OpLow, p.line), p.line == LineRST) }
+func (p *pin) Get() bool        { return p.r.get(p.line) }
@@ -0,0 +1,123 @@
+package ds1302
+
+import (
+    "context"
+)
+
+// ReadRAMBurstContext читает len(buf) байт ОЗУ начиная с нулевого одной
+// пакетной операцией. Отмена ctx проверяется перед каждым байтом; при
+// отмене обмен завершается и возвращается ctx.Err(), а buf заполнен частично.
+func (d *DS1302) ReadRAMBurstContext(ctx context.Context, buf []byte) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if len(buf) > RAMSize {
+        return d.fail(ErrRAMOutOfRange)
+    }
+    return d.readBurstContext(ctx, RAMBurstRead, buf)
+}
+
+// WriteRAMBurstContext записывает data в ОЗУ начиная с нулевого байта одной
+// пакетной операцией, снимая защиту от записи на время обмена. Отмена ctx
+// проверяется перед каждым байтом; байты, переданные до отмены, уже записаны.
+func (d *DS1302) WriteRAMBurstContext(ctx context.Context, data []byte) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if len(data) > RAMSize {
+        return d.fail(ErrRAMOutOfRange)
+    }
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    err := d.writeBurstContext(ctx, RAMBurstWrite, data)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    if err == nil && d.ramUsage != nil {
+        for i := range data {
+            d.ramUsage[i]++
+        }
+    }
+    return err
+}
+
+// readBurstContext читает len(buf) байт пакетным чтением по команде cmd,
+// прерывая обмен при отмене ctx.
+func (d *DS1302) readBurstContext(ctx context.Context, cmd Command, buf []byte) error {
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+    d.metrics.Transactions++
+    d.counters.Bursts++
+    d.counters.Bytes++
+    for attempt := 0; ; attempt++ {
+        stable, err := d.readBurstOnce(ctx, cmd, buf, attempt == sampleRetries)
+        if err != nil {
+            return err
+        }
+        if !d.retryRead(attempt, stable) {
+            break
+        }
+    }
+    if l := d.cfg.Logger; l != nil {
+        // Копия в строку не дает буферу вызывающего кода уйти в кучу.
+        l.Debugf("ds1302: burst read %#02x -> % x", uint8(cmd), string(buf))
+    }
+    return nil
+}
+
+// readBurstOnce выполняет один пакетный обмен. При расхождении выборок
+// обмен прерывается: пакет можно только начать заново. В последней
+// попытке (last) пакет дочитывается целиком, чтобы buf не смешивал
+// байты разных попыток.
+func (d *DS1302) readBurstOnce(ctx context.Context, cmd Command, buf []byte, last bool) (stable bool, err error) {
+    stable = true
+    d.begin()     // Начать передачу
+    d.writeByte(uint8(cmd))
+    for i := range buf {
+        if err := ctx.Err(); err != nil {
+            d.end()
+            return false, err
+        }
+        var ok bool
+        buf[i], ok = d.readByte()
+        d.counters.Bytes++
+        if !ok {
+            stable = false
+            if !last {
+                break
+            }
+        }
+    }
+    d.end()       // Закончить передачу
+    return stable, nil
+}
+
+// writeBurstContext передает data пакетной записью по команде cmd,
+// прерывая обмен при отмене ctx. Защита от записи должна быть снята
+// вызывающим кодом.
+func (d *DS1302) writeBurstContext(ctx context.Context, cmd Command, data []byte) error {
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    d.metrics.Transactions++
+    d.counters.Bursts++
+    d.counters.Bytes++
+    if l := d.cfg.Logger; l != nil {
+        l.Debugf("ds1302: burst write %#02x <- % x", uint8(cmd), string(data))
+    }
+    d.begin()     // Начать передачу
+    d.writeByte(uint8(cmd))
+    for _, b := range data {
+        if err := ctx.Err(); err != nil {
+            d.end()
+            return err
+        }
+        d.writeByte(b)
+        d.counters.Bytes++
+    }
+    d.end()       // Закончить передачу
+    return nil
+}
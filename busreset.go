@@ -0,0 +1,100 @@
+package ds1302
+
+// busFlushClocks - число тактов CLK при сбросе шины: больше длины байта,
+// чтобы дотактировать любой недочитанный бит.
+const busFlushClocks = 9
+
+// busResetDelay - пауза после опускания RST в микросекундах (tCWH
+// по документации DS1302 при питании 2 В - 4 мкс).
+const busResetDelay = 4
+
+// ResetBus возвращает шину в исходное состояние, если обмен был прерван
+// посреди байта (сброс МК, паника, долгое прерывание) и драйвер
+// с микросхемой разошлись в позиции бита: опускает RST, отпускает DAT
+// и дает несколько тактов CLK при опущенном RST.
+//
+// Драйвер вызывает сброс сам после ошибок, указывающих на сбой протокола
+// (ErrInvalidBCD, ErrUnstableRead, ErrVerifyFailed), и перед обменом,
+// если прежний не был завершен. Сбросы учитываются в Metrics.BusResets.
+func (d *DS1302) ResetBus() error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    d.resetBus()
+    return nil
+}
+
+// resetBus выполняет последовательность сброса шины.
+func (d *DS1302) resetBus() {
+    if d.closed {
+        return
+    }
+    d.lockBus()
+    defer d.unlockBus()
+    d.resetLines()
+}
+
+// resetLines сбрасывает линии; замок шины должен быть взят.
+func (d *DS1302) resetLines() {
+    d.metrics.BusResets++
+    if l := d.cfg.Logger; l != nil {
+        l.Debugf("ds1302: bus reset")
+    }
+    d.rst.Low()
+    d.dat.ConfigureInput()
+    d.clk.Low()
+    d.delay(busResetDelay)
+    for i := 0; i < busFlushClocks; i++ {
+        d.clk.High()
+        d.delay(edgeDelay)
+        d.clk.Low()
+        d.delay(edgeDelay)
+    }
+    d.dat.ConfigureOutput()
+    d.dat.Low()
+    d.delay(busResetDelay)
+    d.inTransfer = false
+}
+
+// desync сообщает, указывает ли ошибка на рассогласование обмена.
+func desync(err error) bool {
+    return err == ErrInvalidBCD || err == ErrUnstableRead || err == ErrVerifyFailed
+}
+
+// begin начинает обмен, подняв RST. Если прежний обмен не завершился
+// (паника внутри обмена), шина сначала сбрасывается.
+func (d *DS1302) begin() {
+    d.lockBus()
+    if d.inTransfer {
+        d.resetLines()
+    }
+    d.inTransfer = true
+    d.rst.High()
+}
+
+// end завершает обмен, опустив RST, и отпускает замок шины.
+func (d *DS1302) end() {
+    d.rst.Low()
+    d.inTransfer = false
+    d.unlockBus()
+}
+
+// lockBus захватывает Config.BusLocker, если он задан и еще не взят
+// (после паники внутри обмена замок остается за драйвером).
+func (d *DS1302) lockBus() {
+    if d.bus != nil || d.cfg.BusLocker == nil {
+        return
+    }
+    d.bus = d.cfg.BusLocker
+    d.bus.Lock()
+}
+
+// unlockBus отпускает замок, взятый lockBus.
+func (d *DS1302) unlockBus() {
+    if b := d.bus; b != nil {
+        d.bus = nil
+        b.Unlock()
+    }
+}
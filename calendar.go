@@ -0,0 +1,72 @@
+package ds1302
+
+// Weekday - день недели в регистре DS1302. Микросхема лишь увеличивает
+// регистр 1-7 в полночь; драйвер записывает его при установке времени
+// и считает 1 понедельником (ISO 8601), если не задана Config.SundayFirst.
+type Weekday uint8
+
+const (
+    Monday Weekday = iota + 1
+    Tuesday
+    Wednesday
+    Thursday
+    Friday
+    Saturday
+    Sunday
+)
+
+// weekdayOf переводит значение регистра дня недели в Weekday.
+// Значения вне 1-7 возвращаются без изменений.
+func weekdayOf(reg uint8, sundayFirst bool) Weekday {
+    if !sundayFirst || reg < 1 || reg > 7 {
+        return Weekday(reg)
+    }
+    if reg == 1 {
+        return Sunday
+    }
+    return Weekday(reg - 1)
+}
+
+// Month - месяц года (1-12).
+type Month uint8
+
+const (
+    January Month = iota + 1
+    February
+    March
+    April
+    May
+    June
+    July
+    August
+    September
+    October
+    November
+    December
+)
+
+// Короткие английские названия, по три символа на элемент.
+const (
+    weekdayNames = "MonTueWedThuFriSatSun"
+    monthNames   = "JanFebMarAprMayJunJulAugSepOctNovDec"
+)
+
+// String возвращает короткое название дня недели ("Mon") или "???",
+// если значение регистра вне диапазона 1-7.
+func (w Weekday) String() string {
+    if w < Monday || w > Sunday {
+        return "???"
+    }
+    i := int(w-Monday) * 3
+    return weekdayNames[i : i+3]
+}
+
+// String возвращает короткое название месяца ("Jan") или "???",
+// если значение вне диапазона 1-12.
+func (m Month) String() string {
+    if m < January || m > December {
+        return "???"
+    }
+    i := int(m-January) * 3
+    return monthNames[i : i+3]
+}
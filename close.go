@@ -0,0 +1,73 @@
+package ds1302
+
+import (
+    "errors"
+)
+
+// ErrClosed возвращается методами устройства после Close.
+var ErrClosed = errors.New("ds1302: device is closed")
+
+// Close завершает работу с микросхемой: включает защиту от записи,
+// оставляет RST и CLK в низком уровне и, если задана Config.ReleasePinsOnClose,
+// переводит все линии в режим входа (RST подтянут к земле внутри DS1302,
+// поэтому микросхема остается невыбранной). После Close драйвер не трогает
+// линии, а методы возвращают ErrClosed; Init снова делает устройство рабочим.
+// Нужен прошивкам, которые отключают питание RTC или отдают линии другому
+// устройству. Повторный вызов ничего не делает.
+func (d *DS1302) Close() error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if d.closed {
+        return nil
+    }
+    d.release(d.cfg.ReleasePinsOnClose)
+    return nil
+}
+
+// HaltAndRelease готовит модуль к длительному хранению: останавливает
+// генератор (бит CH, значение секунд сохраняется), включает защиту от записи
+// и переводит все три линии в режим входа независимо от
+// Config.ReleasePinsOnClose. Часы стоят и не расходуют батарею на генератор,
+// пока их не запустят снова: Init (если не задана Config.NoAutoStart)
+// или SetTime. Дальше устройство ведет себя как после Close.
+func (d *DS1302) HaltAndRelease() error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    sec := d.readRegister(DS1302_SECONDS_READ)
+    d.writeRegister(DS1302_SECONDS_WRITE, sec|DS1302_CH_BIT)
+    d.release(true)
+    return nil
+}
+
+// release включает защиту от записи, опускает RST и CLK, при tristate
+// переводит линии в режим входа и помечает устройство закрытым.
+func (d *DS1302) release(tristate bool) {
+    d.writeRegister(DS1302_WP_WRITE, DS1302_WP_BIT)
+    d.lockBus()
+    d.rst.Low()
+    d.clk.Low()
+    if tristate {
+        d.clk.ConfigureInput()
+        d.dat.ConfigureInput()
+        d.rst.ConfigureInput()
+    }
+    d.inTransfer = false
+    d.unlockBus()
+    d.closed = true
+}
+
+// checkOpen возвращает ошибку назначения линий или ErrClosed,
+// если устройство закрыто.
+func (d *DS1302) checkOpen() error {
+    if d.pinErr != nil {
+        return d.fail(d.pinErr)
+    }
+    if d.closed {
+        return d.fail(ErrClosed)
+    }
+    return nil
+}
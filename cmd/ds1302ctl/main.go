@@ -0,0 +1,216 @@
+// Команда ds1302ctl - утилита для стендовой подготовки модулей DS1302
+// перед сборкой: чтение и установка времени, работа с резервным ОЗУ,
+// просмотр регистров и настройка подзарядки.
+//
+// Микросхема подключается к хосту через Linux gpiod (/dev/gpiochipN)
+// или USB-мост FT232H:
+//
+//     ds1302ctl [-backend gpiod|ft232h] [-clk PIN] [-dat PIN] [-rst PIN] <команда>
+//
+// Команды:
+//
+//     get                       вывести текущее время RTC
+//     set <RFC3339>             установить время
+//     set --from-system         установить системное время хоста (UTC)
+//     ram dump                  вывести содержимое ОЗУ
+//     ram write <addr> <hex>... записать байты в ОЗУ начиная с addr
+//     regs                      вывести сырые значения регистров
+//     trickle [<hex>]           прочитать или записать регистр подзарядки
+package main
+
+import (
+    "errors"
+    "flag"
+    "fmt"
+    "os"
+    "strconv"
+    "time"
+
+    "github.com/golangworker/ds1302-driver"
+    "github.com/golangworker/ds1302-driver/cmd/internal/hostpin"
+)
+
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+    var cfg hostpin.Config
+    flag.StringVar(&cfg.Backend, "backend", hostpin.Gpiod, "GPIO backend: gpiod or ft232h")
+    flag.StringVar(&cfg.CLK, "clk", "", "CLK pin name (default depends on backend)")
+    flag.StringVar(&cfg.DAT, "dat", "", "DAT pin name (default depends on backend)")
+    flag.StringVar(&cfg.RST, "rst", "", "RST pin name (default depends on backend)")
+    flag.Usage = usage
+    flag.Parse()
+
+    if flag.NArg() == 0 {
+        usage()
+        os.Exit(2)
+    }
+
+    dev, err := hostpin.Open(cfg)
+    if err != nil {
+        fmt.Fprintln(os.Stderr, "ds1302ctl:", err)
+        os.Exit(1)
+    }
+
+    err = run(dev, flag.Args())
+    if err == nil {
+        err = dev.Err()
+    }
+    if err == errUsage {
+        usage()
+        os.Exit(2)
+    }
+    if err != nil {
+        fmt.Fprintln(os.Stderr, "ds1302ctl:", err)
+        os.Exit(1)
+    }
+}
+
+func usage() {
+    fmt.Fprintln(os.Stderr, `usage: ds1302ctl [flags] <command>
+
+commands:
+  get                        print RTC time
+  set <RFC3339>              set RTC time
+  set --from-system          set RTC time from host clock (UTC)
+  ram dump                   print backup RAM
+  ram write <addr> <hex>...  write bytes to backup RAM starting at addr
+  regs                       print raw register values
+  trickle [<hex>]            read or write trickle-charge register
+
+flags:`)
+    flag.PrintDefaults()
+}
+
+func run(dev *hostpin.Device, args []string) error {
+    switch args[0] {
+    case "get":
+        fmt.Println(dev.ReadTime().Format(time.RFC3339))
+        return nil
+    case "set":
+        return set(dev, args[1:])
+    case "ram":
+        return ram(dev, args[1:])
+    case "regs":
+        return regs(dev)
+    case "trickle":
+        return trickle(dev, args[1:])
+    }
+    return errUsage
+}
+
+func set(dev *hostpin.Device, args []string) error {
+    if len(args) != 1 {
+        return errUsage
+    }
+
+    var t time.Time
+    if args[0] == "--from-system" {
+        t = time.Now().UTC()
+    } else {
+        var err error
+        t, err = time.Parse(time.RFC3339, args[0])
+        if err != nil {
+            return err
+        }
+    }
+    dev.Configure(ds1302.Config{VerifyWrites: true, NoAutoStart: true})
+    if err := dev.SetTime(t); err != nil {
+        return err
+    }
+    fmt.Println(t.Format(time.RFC3339))
+    return nil
+}
+
+func ram(dev *hostpin.Device, args []string) error {
+    if len(args) == 0 {
+        return errUsage
+    }
+
+    switch args[0] {
+    case "dump":
+        buf := make([]byte, ds1302.RAMSize)
+        if _, err := dev.RAMBlockDevice().ReadAt(buf, 0); err != nil {
+            return err
+        }
+        for i, b := range buf {
+            if i%8 == 0 {
+                if i > 0 {
+                    fmt.Println()
+                }
+                fmt.Printf("%02d:", i)
+            }
+            fmt.Printf(" %02x", b)
+        }
+        fmt.Println()
+        return nil
+    case "write":
+        if len(args) < 3 {
+            return errUsage
+        }
+        addr, err := strconv.ParseUint(args[1], 0, 8)
+        if err != nil {
+            return err
+        }
+        data := make([]byte, 0, len(args)-2)
+        for _, a := range args[2:] {
+            b, err := strconv.ParseUint(a, 16, 8)
+            if err != nil {
+                return err
+            }
+            data = append(data, uint8(b))
+        }
+        return dev.WriteRAMBytes(uint8(addr), data)
+    }
+    return errUsage
+}
+
+// registers - регистры, выводимые командой regs.
+var registers = []ds1302.ClockRegister{
+    ds1302.RegSeconds,
+    ds1302.RegMinutes,
+    ds1302.RegHours,
+    ds1302.RegDate,
+    ds1302.RegMonth,
+    ds1302.RegWeekday,
+    ds1302.RegYear,
+    ds1302.RegWriteProtect,
+    ds1302.RegTrickle,
+}
+
+func regs(dev *hostpin.Device) error {
+    for _, r := range registers {
+        cmd := ds1302.ClockRead(r)
+        v, err := dev.ReadRegister(cmd)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("0x%02x %-20s = 0x%02x\n", uint8(cmd), cmd, v)
+    }
+    return nil
+}
+
+func trickle(dev *hostpin.Device, args []string) error {
+    if len(args) > 1 {
+        return errUsage
+    }
+    if len(args) == 1 {
+        v, err := strconv.ParseUint(args[0], 16, 8)
+        if err != nil {
+            return err
+        }
+        err = dev.Transaction(func(tx *ds1302.Tx) error {
+            return tx.WriteRegister(ds1302.ClockWrite(ds1302.RegTrickle), uint8(v))
+        })
+        if err != nil {
+            return err
+        }
+    }
+
+    v, err := dev.ReadRegister(ds1302.ClockRead(ds1302.RegTrickle))
+    if err != nil {
+        return err
+    }
+    fmt.Printf("0x%02x\n", v)
+    return nil
+}
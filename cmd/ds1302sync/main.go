@@ -0,0 +1,102 @@
+// Команда ds1302sync передает текущее время рабочей станции
+// на устройство по USB-serial сразу после прошивки.
+//
+// Прошивка должна обрабатывать протокол пакета provision:
+//
+//     ds1302sync [-baud 115200] [-timeout 2s] /dev/ttyUSB0
+//
+// Программа дожидается начала следующей секунды хоста, отправляет время,
+// получает ответ с прочитанным временем RTC и выводит расхождение.
+package main
+
+import (
+    "errors"
+    "flag"
+    "fmt"
+    "os"
+    "time"
+
+    "github.com/golangworker/ds1302-driver/provision"
+    "go.bug.st/serial"
+)
+
+func main() {
+    baud := flag.Int("baud", 115200, "serial baud rate")
+    timeout := flag.Duration("timeout", 2*time.Second, "time to wait for device reply")
+    get := flag.Bool("get", false, "only read device time, do not set it")
+    flag.Usage = func() {
+        fmt.Fprintln(os.Stderr, "usage: ds1302sync [flags] <serial port>")
+        flag.PrintDefaults()
+    }
+    flag.Parse()
+
+    if flag.NArg() != 1 {
+        flag.Usage()
+        os.Exit(2)
+    }
+
+    if err := run(flag.Arg(0), *baud, *timeout, *get); err != nil {
+        fmt.Fprintln(os.Stderr, "ds1302sync:", err)
+        os.Exit(1)
+    }
+}
+
+func run(name string, baud int, timeout time.Duration, get bool) error {
+    port, err := serial.Open(name, &serial.Mode{BaudRate: baud})
+    if err != nil {
+        return err
+    }
+    defer port.Close()
+
+    req := provision.Frame{Type: provision.TypeGetTime}
+    if !get {
+        // RTC хранит только целые секунды: отправляем время
+        // в самом начале секунды хоста, чтобы не терять ее долю.
+        now := time.Now()
+        time.Sleep(now.Truncate(time.Second).Add(time.Second).Sub(now))
+        req = provision.Frame{Type: provision.TypeSetTime, Payload: provision.AppendTime(nil, time.Now())}
+    }
+    if err := provision.WriteFrame(port, req); err != nil {
+        return err
+    }
+
+    reply, err := readFrame(port, timeout)
+    if err != nil {
+        return err
+    }
+    switch reply.Type {
+    case provision.TypeTime:
+        t, err := provision.ParseTime(reply.Payload)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("device: %s (offset %v)\n", t.Format(time.RFC3339), t.Sub(time.Now().Truncate(time.Second)))
+        return nil
+    case provision.TypeError:
+        return fmt.Errorf("device rejected request: % x", reply.Payload)
+    }
+    return fmt.Errorf("unexpected reply type 0x%02x", reply.Type)
+}
+
+// readFrame читает из порта байты, пока не будет принят кадр или не истечет timeout.
+func readFrame(port serial.Port, timeout time.Duration) (provision.Frame, error) {
+    if err := port.SetReadTimeout(100 * time.Millisecond); err != nil {
+        return provision.Frame{}, err
+    }
+
+    var dec provision.Decoder
+    buf := make([]byte, 64)
+    deadline := time.Now().Add(timeout)
+    for time.Now().Before(deadline) {
+        n, err := port.Read(buf)
+        if err != nil {
+            return provision.Frame{}, err
+        }
+        for _, b := range buf[:n] {
+            if f, ok := dec.Feed(b); ok {
+                return f, nil
+            }
+        }
+    }
+    return provision.Frame{}, errors.New("timeout waiting for device reply")
+}
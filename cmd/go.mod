@@ -0,0 +1,18 @@
+module github.com/golangworker/ds1302-driver/cmd
+
+go 1.22.6
+
+require (
+	github.com/golangworker/ds1302-driver v0.0.0
+	go.bug.st/serial v1.6.4
+	periph.io/x/conn/v3 v3.7.2
+	periph.io/x/host/v3 v3.8.5
+)
+
+require (
+	github.com/creack/goselect v0.1.2 // indirect
+	golang.org/x/sys v0.19.0 // indirect
+	periph.io/x/d2xx v0.1.1 // indirect
+)
+
+replace github.com/golangworker/ds1302-driver => ../
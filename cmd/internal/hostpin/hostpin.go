@@ -0,0 +1,142 @@
+// Package hostpin подключает DS1302 к линиям GPIO хост-компьютера
+// через periph.io: символьное устройство Linux gpiod (/dev/gpiochipN)
+// или USB-мост FT232H.
+package hostpin
+
+import (
+    "fmt"
+    "sync"
+
+    "github.com/golangworker/ds1302-driver"
+    "periph.io/x/conn/v3/gpio"
+    "periph.io/x/conn/v3/gpio/gpioreg"
+    "periph.io/x/host/v3"
+    _ "periph.io/x/host/v3/ftdi"
+    _ "periph.io/x/host/v3/gpioioctl"
+)
+
+// Имена поддерживаемых бэкендов.
+const (
+    Gpiod  = "gpiod"
+    FT232H = "ft232h"
+)
+
+// Пины по умолчанию для каждого бэкенда: CLK, DAT, RST.
+var defaultPins = map[string][3]string{
+    Gpiod:  {"GPIO17", "GPIO27", "GPIO22"},
+    FT232H: {"D0", "D1", "D2"},
+}
+
+// Config описывает подключение микросхемы к хосту.
+type Config struct {
+    Backend string // Gpiod или FT232H
+    CLK     string // Имя линии CLK (пусто - по умолчанию для бэкенда)
+    DAT     string // Имя линии DAT
+    RST     string // Имя линии RST
+}
+
+// Device - драйвер DS1302, подключенный к линиям хоста.
+type Device struct {
+    *ds1302.DS1302
+    errs *errorSink
+}
+
+// Err возвращает первую ошибку ввода-вывода GPIO с момента прошлого вызова.
+func (d *Device) Err() error {
+    return d.errs.take()
+}
+
+// Open инициализирует periph.io, находит линии и возвращает готовый драйвер.
+func Open(cfg Config) (*Device, error) {
+    defaults, ok := defaultPins[cfg.Backend]
+    if !ok {
+        return nil, fmt.Errorf("hostpin: unknown backend %q", cfg.Backend)
+    }
+    names := [3]string{cfg.CLK, cfg.DAT, cfg.RST}
+    for i := range names {
+        if names[i] == "" {
+            names[i] = defaults[i]
+        }
+    }
+
+    if _, err := host.Init(); err != nil {
+        return nil, fmt.Errorf("hostpin: %w", err)
+    }
+
+    errs := &errorSink{}
+    var pins [3]ds1302.Pin
+    for i, name := range names {
+        p := gpioreg.ByName(name)
+        if p == nil {
+            return nil, fmt.Errorf("hostpin: %s: pin %q not found", cfg.Backend, name)
+        }
+        pins[i] = pin{p: p, errs: errs}
+    }
+
+    dev := &Device{DS1302: ds1302.New(pins[0], pins[1], pins[2]), errs: errs}
+    // Инструменты показывают микросхему как есть, не запуская генератор.
+    dev.Configure(ds1302.Config{NoAutoStart: true})
+    initErr := dev.Init()
+    // Ошибка GPIO точнее описывает причину, чем ошибка Init.
+    if err := dev.Err(); err != nil {
+        return nil, err
+    }
+    if initErr != nil {
+        return nil, fmt.Errorf("hostpin: %w", initErr)
+    }
+    return dev, nil
+}
+
+// pin реализует ds1302.Pin поверх gpio.PinIO.
+type pin struct {
+    p    gpio.PinIO
+    errs *errorSink
+}
+
+func (p pin) ConfigureOutput() {
+    p.errs.record(p.p.Out(gpio.Low))
+}
+
+func (p pin) ConfigureInput() {
+    p.errs.record(p.p.In(gpio.PullNoChange, gpio.NoEdge))
+}
+
+func (p pin) High() {
+    p.errs.record(p.p.Out(gpio.High))
+}
+
+func (p pin) Low() {
+    p.errs.record(p.p.Out(gpio.Low))
+}
+
+func (p pin) Get() bool {
+    return p.p.Read() == gpio.High
+}
+
+// errorSink хранит первую ошибку GPIO до ее извлечения.
+type errorSink struct {
+    mu  sync.Mutex
+    err error
+}
+
+func (s *errorSink) record(err error) {
+    if err == nil {
+        return
+    }
+    s.mu.Lock()
+    if s.err == nil {
+        s.err = err
+    }
+    s.mu.Unlock()
+}
+
+func (s *errorSink) take() error {
+    s.mu.Lock()
+    err := s.err
+    s.err = nil
+    s.mu.Unlock()
+    if err != nil {
+        return fmt.Errorf("hostpin: GPIO error: %w", err)
+    }
+    return nil
+}
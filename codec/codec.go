@@ -0,0 +1,123 @@
+// Package codec преобразует снимок регистров часов RTC в time.Time и обратно:
+// BCD, 12/24-часовой формат, бит остановки генератора и номер дня недели.
+//
+// Пакет не обращается к шине и не зависит от machine и тегов сборки,
+// поэтому самый тонкий по корректности код драйвера проверяется обычным
+// go test на хосте. Раскладка регистров совпадает у DS1302 и DS1307
+// (отличается только порядок в пакетном чтении), так что пакет годится
+// для обоих драйверов: вызывающий код раскладывает байты по полям Regs.
+package codec
+
+import (
+    "errors"
+    "time"
+
+    "github.com/golangworker/ds1302-driver/bcd"
+)
+
+// ErrInvalidBCD возвращается Decode, если регистр часов содержит
+// недопустимое BCD значение.
+var ErrInvalidBCD = errors.New("ds1302: invalid BCD value in clock register")
+
+// Биты регистров часов.
+const (
+    HaltBit = 0x80 // Бит CH регистра секунд: генератор остановлен
+    Bit12H  = 0x80 // Бит 12/24 регистра часов: 12-часовой формат
+    BitPM   = 0x20 // Бит AM/PM регистра часов в 12-часовом формате
+)
+
+// HourMode - формат хранения часов в регистре.
+type HourMode uint8
+
+const (
+    Hour24 HourMode = iota // 24-часовой формат (0-23)
+    Hour12                 // 12-часовой формат с битом AM/PM
+)
+
+// Regs - значения регистров часов в формате микросхемы.
+type Regs struct {
+    Seconds uint8 // BCD 00-59, бит 7 - HaltBit
+    Minutes uint8 // BCD 00-59
+    Hours   uint8 // BCD; формат задает Bit12H
+    Date    uint8 // BCD 01-31
+    Month   uint8 // BCD 01-12
+    Weekday uint8 // 1-7 (см. WeekdayReg)
+    Year    uint8 // BCD 00-99 - 2000-2099
+}
+
+// Decode преобразует регистры в время UTC. Бит остановки генератора
+// и регистр дня недели не учитываются, часы декодируются в обоих форматах.
+// При недопустимом BCD возвращает ErrInvalidBCD вместе с временем,
+// собранным из тех же регистров, как их прочитал бы драйвер без проверки.
+func Decode(r Regs) (time.Time, error) {
+    sec := r.Seconds &^ HaltBit
+    hours := r.Hours
+    if hours&Bit12H != 0 {
+        hours &= 0x1F
+    }
+
+    var err error
+    for _, v := range [...]uint8{sec, r.Minutes, hours, r.Date, r.Month, r.Year} {
+        if !bcd.Valid(v) {
+            err = ErrInvalidBCD
+            break
+        }
+    }
+
+    return time.Date(2000+int(bcd.ToDec(r.Year)), time.Month(bcd.ToDec(r.Month)), int(bcd.ToDec(r.Date)),
+        int(DecodeHours(r.Hours)), int(bcd.ToDec(r.Minutes)), int(bcd.ToDec(sec)), 0, time.UTC), err
+}
+
+// Encode преобразует время t (часть UTC, 2000-2099) в регистры с часами
+// в формате mode и номером дня недели по WeekdayReg. Генератор в Seconds
+// запущен; доли секунды отбрасываются.
+func Encode(t time.Time, mode HourMode, sundayFirst bool) Regs {
+    return Regs{
+        Seconds: bcd.FromDec(uint8(t.Second())),
+        Minutes: bcd.FromDec(uint8(t.Minute())),
+        Hours:   EncodeHours(uint8(t.Hour()), mode),
+        Date:    bcd.FromDec(uint8(t.Day())),
+        Month:   bcd.FromDec(uint8(t.Month())),
+        Weekday: WeekdayReg(t.Weekday(), sundayFirst),
+        Year:    bcd.FromDec(uint8(t.Year() - 2000)),
+    }
+}
+
+// EncodeHours кодирует часы (0-23) в значение регистра часов в формате mode.
+func EncodeHours(hours uint8, mode HourMode) uint8 {
+    if mode == Hour24 {
+        return bcd.FromDec(hours)
+    }
+    h, pm := bcd.To12(hours)
+    reg := uint8(Bit12H)
+    if pm {
+        reg |= BitPM
+    }
+    return reg | bcd.FromDec(h)
+}
+
+// DecodeHours декодирует регистр часов в 24-часовое значение (0-23)
+// с учетом 12-часового формата.
+func DecodeHours(reg uint8) uint8 {
+    if reg&Bit12H == 0 {
+        return bcd.ToDec(reg & 0x3F)
+    }
+    return bcd.To24(bcd.ToDec(reg&0x1F), reg&BitPM != 0)
+}
+
+// HourModeOf возвращает формат часов по значению регистра часов.
+func HourModeOf(reg uint8) HourMode {
+    if reg&Bit12H != 0 {
+        return Hour12
+    }
+    return Hour24
+}
+
+// WeekdayReg возвращает значение регистра дня недели для wd:
+// 1 - понедельник (ISO 8601) или, при sundayFirst, 1 - воскресенье.
+func WeekdayReg(wd time.Weekday, sundayFirst bool) uint8 {
+    if sundayFirst {
+        return uint8(wd) + 1
+    }
+    return (uint8(wd)+6)%7 + 1
+}
@@ -0,0 +1,140 @@
+package codec
+
+import (
+    "testing"
+    "time"
+)
+
+func TestDecodeKnown(t *testing.T) {
+    tests := []struct {
+        name string
+        regs Regs
+        want time.Time
+    }{
+        {"24h", Regs{0x56, 0x34, 0x12, 0x31, 0x12, 0x07, 0x99}, time.Date(2099, 12, 31, 12, 34, 56, 0, time.UTC)},
+        {"power-on", Regs{0x80, 0x00, 0x00, 0x01, 0x01, 0x01, 0x00}, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+        {"12h midnight", Regs{0x00, 0x00, 0x92, 0x15, 0x06, 0x06, 0x24}, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)},
+        {"12h 1 AM", Regs{0x00, 0x00, 0x81, 0x15, 0x06, 0x06, 0x24}, time.Date(2024, 6, 15, 1, 0, 0, 0, time.UTC)},
+        {"12h noon", Regs{0x00, 0x00, 0xB2, 0x15, 0x06, 0x06, 0x24}, time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)},
+        {"12h 11 PM", Regs{0x59, 0x59, 0xB1, 0x29, 0x02, 0x04, 0x24}, time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)},
+    }
+    for _, tt := range tests {
+        got, err := Decode(tt.regs)
+        if err != nil {
+            t.Errorf("%s: Decode error %v", tt.name, err)
+        }
+        if !got.Equal(tt.want) {
+            t.Errorf("%s: Decode = %v, want %v", tt.name, got, tt.want)
+        }
+    }
+}
+
+func TestDecodeInvalidBCD(t *testing.T) {
+    valid := Regs{0x00, 0x00, 0x00, 0x01, 0x01, 0x01, 0x00}
+    fields := []func(r *Regs){
+        func(r *Regs) { r.Seconds = 0x0A },
+        func(r *Regs) { r.Minutes = 0x5F },
+        func(r *Regs) { r.Hours = 0x1A },
+        func(r *Regs) { r.Hours = 0x80 | 0x0C },
+        func(r *Regs) { r.Date = 0xA1 },
+        func(r *Regs) { r.Month = 0x1B },
+        func(r *Regs) { r.Year = 0xFF },
+    }
+    for i, set := range fields {
+        r := valid
+        set(&r)
+        if _, err := Decode(r); err != ErrInvalidBCD {
+            t.Errorf("field %d: Decode(%+v) error = %v, want ErrInvalidBCD", i, r, err)
+        }
+    }
+    // Недопустимый день недели не проверяется: микросхема его лишь считает.
+    r := valid
+    r.Weekday = 0xFF
+    if _, err := Decode(r); err != nil {
+        t.Errorf("Decode with weekday 0xFF: %v", err)
+    }
+}
+
+func TestRoundTrip(t *testing.T) {
+    start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+    end := time.Date(2099, 12, 31, 23, 59, 59, 0, time.UTC)
+    // Шаг не кратен ни минуте, ни часу, ни суткам, поэтому за проход
+    // встречаются все значения секунд, минут, часов и дней.
+    step := 7*time.Hour + 13*time.Minute + 17*time.Second
+    for tm := start; !tm.After(end); tm = tm.Add(step) {
+        for _, mode := range []HourMode{Hour24, Hour12} {
+            for _, sundayFirst := range []bool{false, true} {
+                r := Encode(tm, mode, sundayFirst)
+                if HourModeOf(r.Hours) != mode {
+                    t.Fatalf("%v %v: hour register %#02x in wrong mode", tm, mode, r.Hours)
+                }
+                if r.Seconds&HaltBit != 0 {
+                    t.Fatalf("%v: Encode set HaltBit", tm)
+                }
+                got, err := Decode(r)
+                if err != nil || !got.Equal(tm) {
+                    t.Fatalf("Decode(Encode(%v, %v)) = %v, %v", tm, mode, got, err)
+                }
+                if want := WeekdayReg(tm.Weekday(), sundayFirst); r.Weekday != want {
+                    t.Fatalf("%v: weekday register %d, want %d", tm, r.Weekday, want)
+                }
+            }
+        }
+    }
+}
+
+func TestEncodeTruncates(t *testing.T) {
+    tm := time.Date(2024, 3, 10, 8, 5, 9, 999999999, time.UTC)
+    got, err := Decode(Encode(tm, Hour24, false))
+    if err != nil || !got.Equal(tm.Truncate(time.Second)) {
+        t.Fatalf("Decode(Encode(%v)) = %v, %v", tm, got, err)
+    }
+}
+
+func TestHours(t *testing.T) {
+    want12 := [24]uint8{
+        0x92, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x90, 0x91,
+        0xB2, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7, 0xA8, 0xA9, 0xB0, 0xB1,
+    }
+    for h := uint8(0); h < 24; h++ {
+        if got := EncodeHours(h, Hour12); got != want12[h] {
+            t.Errorf("EncodeHours(%d, Hour12) = %#02x, want %#02x", h, got, want12[h])
+        }
+        if got := DecodeHours(want12[h]); got != h {
+            t.Errorf("DecodeHours(%#02x) = %d, want %d", want12[h], got, h)
+        }
+        reg24 := EncodeHours(h, Hour24)
+        if reg24 != h/10<<4|h%10 {
+            t.Errorf("EncodeHours(%d, Hour24) = %#02x", h, reg24)
+        }
+        if got := DecodeHours(reg24); got != h {
+            t.Errorf("DecodeHours(%#02x) = %d, want %d", reg24, got, h)
+        }
+        if HourModeOf(reg24) != Hour24 || HourModeOf(want12[h]) != Hour12 {
+            t.Errorf("HourModeOf wrong for hour %d", h)
+        }
+    }
+}
+
+func TestWeekdayReg(t *testing.T) {
+    tests := []struct {
+        wd          time.Weekday
+        iso, sunday uint8
+    }{
+        {time.Monday, 1, 2},
+        {time.Tuesday, 2, 3},
+        {time.Wednesday, 3, 4},
+        {time.Thursday, 4, 5},
+        {time.Friday, 5, 6},
+        {time.Saturday, 6, 7},
+        {time.Sunday, 7, 1},
+    }
+    for _, tt := range tests {
+        if got := WeekdayReg(tt.wd, false); got != tt.iso {
+            t.Errorf("WeekdayReg(%v, false) = %d, want %d", tt.wd, got, tt.iso)
+        }
+        if got := WeekdayReg(tt.wd, true); got != tt.sunday {
+            t.Errorf("WeekdayReg(%v, true) = %d, want %d", tt.wd, got, tt.sunday)
+        }
+    }
+}
@@ -0,0 +1,102 @@
+package ds1302
+
+import (
+    "errors"
+    "time"
+)
+
+// ErrVerifyFailed возвращается при включенной Config.VerifyWrites,
+// если прочитанное после записи время не совпало с записанным.
+var ErrVerifyFailed = errors.New("ds1302: clock read-back does not match written time")
+
+// Config - необязательные параметры драйвера (см. Configure).
+// Нулевое значение соответствует поведению по умолчанию.
+type Config struct {
+    // VerifyWrites заставляет SetTime и родственные методы перечитывать
+    // регистры часов после записи и возвращать ErrVerifyFailed, если время
+    // расходится с записанным больше чем на секунду. Позволяет сценариям
+    // настройки убедиться, что запись действительно дошла до микросхемы.
+    VerifyWrites bool
+
+    // Logger получает трассировку каждой транзакции на шине (Debugf)
+    // и каждой ошибки драйвера (Errorf). Помогает разбираться с клонами
+    // микросхемы в поле. nil отключает трассировку без накладных расходов.
+    Logger Logger
+
+    // DelayFn заменяет паузу между фронтами CLK; cycles - требуемая длительность
+    // в микросекундах. Нужна на платформах, где time.Sleep неточен или недоступен,
+    // и в симуляторах, где паузы можно пропускать. nil - встроенная реализация.
+    DelayFn func(cycles uint32)
+
+    // ValidFrom и ValidUntil задают окно, вне которого IsTimeValid считает
+    // время неправдоподобным, например дату сборки прошивки и ее плюс
+    // двадцать лет. Нулевое значение отключает соответствующую границу.
+    ValidFrom  time.Time
+    ValidUntil time.Time
+
+    // ReleasePinsOnClose заставляет Close перевести линии в режим входа,
+    // например чтобы отдать их другому устройству или не питать через них
+    // обесточенную микросхему.
+    ReleasePinsOnClose bool
+
+    // SundayFirst задает нумерацию регистра дня недели, который драйвер
+    // записывает при каждой установке времени: false - 1 понедельник
+    // (ISO 8601), true - 1 воскресенье, как в библиотеках Arduino.
+    // ReadDateTime в обоих случаях возвращает Weekday с понедельником 1.
+    SundayFirst bool
+
+    // NoAutoStart запрещает Init запускать остановленный генератор.
+    // По умолчанию Init сбрасывает бит CH, поэтому новый модуль, который
+    // поставляется с остановленными часами, начинает считать время сразу,
+    // без отдельного шага. Замеченная при этом остановка запоминается,
+    // и Trust возвращает TimeInvalid, пока часы не будут установлены.
+    // Флаг нужен, чтобы сохранить часы остановленными (см. HaltAndRelease)
+    // или увидеть состояние микросхемы как есть.
+    // Действует, если Configure вызван до Init.
+    NoAutoStart bool
+
+    // DoubleSample включает защиту от помех для длинных линий рядом с
+    // реле и силовыми цепями: каждый бит читается дважды, пока CLK в высоком
+    // уровне, и при расхождении байт перечитывается заново (до трех повторов,
+    // см. Metrics.Retries и ErrUnstableRead). Удлиняет чтение примерно в полтора раза.
+    DoubleSample bool
+
+    // BusLocker, если задан, захватывается на каждую транзакцию на шине
+    // (и на сброс или настройку линий), пока поднят RST. Нужен платам,
+    // где CLK или DAT общие с другими программными интерфейсами (еще один
+    // DS1302, сдвиговый регистр, дисплей): драйверы этих устройств берут
+    // тот же замок, и обмены не перемешиваются.
+    BusLocker BusLocker
+}
+
+// BusLocker сериализует доступ к общим линиям; ему удовлетворяет *sync.Mutex.
+// Замок берется на одну транзакцию (микросекунды), а не на вызов метода,
+// поэтому другие устройства не ждут, например, WaitForSecondEdge целиком.
+type BusLocker interface {
+    Lock()
+    Unlock()
+}
+
+// Configure задает необязательные параметры драйвера.
+func (d *DS1302) Configure(cfg Config) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    d.cfg = cfg
+}
+
+// verifyTime при включенной VerifyWrites сверяет регистры часов
+// с только что записанным временем t.
+func (d *DS1302) verifyTime(t time.Time) error {
+    if !d.cfg.VerifyWrites {
+        return nil
+    }
+    got, err := decodeTime(d.readTimeRegs())
+    if err != nil {
+        return d.fail(ErrVerifyFailed)
+    }
+    diff := got.Sub(t.Truncate(time.Second))
+    if diff < 0 || diff > time.Second {
+        return d.fail(ErrVerifyFailed)
+    }
+    return nil
+}
@@ -0,0 +1,25 @@
+package ds1302
+
+// Counters - сырые счетчики обмена по шине. В отличие от Metrics они не
+// говорят о здоровье RTC, а позволяют измерить нагрузку на программную
+// реализацию протокола на реальном устройстве (см. ResetCounters).
+type Counters struct {
+    Reads  uint32 // Чтения одного регистра
+    Writes uint32 // Записи одного регистра
+    Bursts uint32 // Пакетные обмены
+    Bytes  uint32 // Байт на линии в обе стороны, включая байты команд
+}
+
+// Counters возвращает снимок счетчиков обмена.
+func (d *DS1302) Counters() Counters {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.counters
+}
+
+// ResetCounters обнуляет счетчики обмена, например перед замером.
+func (d *DS1302) ResetCounters() {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    d.counters = Counters{}
+}
@@ -0,0 +1,18 @@
+package ds1302
+
+// crc16 вычисляет CRC-16/CCITT-FALSE (полином 0x1021, начальное значение 0xFFFF),
+// тот же, что используется в протоколе provision.
+func crc16(data []byte) uint16 {
+    crc := uint16(0xFFFF)
+    for _, b := range data {
+        crc ^= uint16(b) << 8
+        for i := 0; i < 8; i++ {
+            if crc&0x8000 != 0 {
+                crc = crc<<1 ^ 0x1021
+            } else {
+                crc <<= 1
+            }
+        }
+    }
+    return crc
+}
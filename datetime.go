@@ -0,0 +1,104 @@
+package ds1302
+
+import (
+    "time"
+
+    "github.com/golangworker/ds1302-driver/bcd"
+)
+
+// DateTime - дата и время в том виде, в котором их хранит DS1302.
+// Позволяет работать с часами и выводить время без пакета time,
+// что экономит флеш-память и выделения памяти на микроконтроллерах.
+type DateTime struct {
+    Year    uint16  // Год (2000-2099)
+    Month   Month   // Месяц (1-12)
+    Day     uint8   // День месяца (1-31)
+    Hour    uint8   // Часы (0-23)
+    Minute  uint8   // Минуты (0-59)
+    Second  uint8   // Секунды (0-59)
+    Weekday Weekday // День недели из регистра DS1302 (Monday-Sunday)
+}
+
+// ReadDateTime читает дату и время из DS1302 без преобразования в time.Time.
+// Время и день недели берутся из одного пакетного чтения, поэтому
+// в полночь день недели не может относиться к следующим суткам.
+// При недопустимых значениях в регистрах возвращается ErrInvalidBCD.
+func (d *DS1302) ReadDateTime() (DateTime, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return DateTime{}, err
+    }
+    var b [7]uint8
+    d.readBurst(ClockBurstRead, b[:])
+    t, err := decodeTime([6]uint8{b[0], b[1], b[2], b[3], b[4], b[6]})
+    if err != nil {
+        return DateTime{}, d.fail(err)
+    }
+    return DateTime{
+        Year:    uint16(t.Year()),
+        Month:   Month(t.Month()),
+        Day:     uint8(t.Day()),
+        Hour:    uint8(t.Hour()),
+        Minute:  uint8(t.Minute()),
+        Second:  uint8(t.Second()),
+        Weekday: weekdayOf(bcd.ToDec(b[5]), d.cfg.SundayFirst),
+    }, nil
+}
+
+// Time преобразует DateTime в time.Time в UTC.
+func (dt DateTime) Time() time.Time {
+    return time.Date(int(dt.Year), time.Month(dt.Month), int(dt.Day),
+                    int(dt.Hour), int(dt.Minute), int(dt.Second), 0, time.UTC)
+}
+
+// AppendClock добавляет к dst время в формате "15:04:05".
+func (dt DateTime) AppendClock(dst []byte) []byte {
+    dst = append2(dst, dt.Hour)
+    dst = append(dst, ':')
+    dst = append2(dst, dt.Minute)
+    dst = append(dst, ':')
+    return append2(dst, dt.Second)
+}
+
+// AppendDate добавляет к dst дату в формате "2006-01-02".
+func (dt DateTime) AppendDate(dst []byte) []byte {
+    dst = append2(dst, uint8(dt.Year/100))
+    dst = append2(dst, uint8(dt.Year%100))
+    dst = append(dst, '-')
+    dst = append2(dst, uint8(dt.Month))
+    dst = append(dst, '-')
+    return append2(dst, dt.Day)
+}
+
+// AppendRFC3339 добавляет к dst время в формате RFC 3339 "2006-01-02T15:04:05Z".
+// В отличие от time.Time.AppendFormat не выделяет память и не тянет
+// форматтер пакета time, поэтому подходит для журналов по UART.
+func (dt DateTime) AppendRFC3339(dst []byte) []byte {
+    dst = dt.AppendDate(dst)
+    dst = append(dst, 'T')
+    dst = dt.AppendClock(dst)
+    return append(dst, 'Z')
+}
+
+// AppendDateTime добавляет к dst время в формате "2006-01-02 15:04:05".
+func (dt DateTime) AppendDateTime(dst []byte) []byte {
+    dst = dt.AppendDate(dst)
+    dst = append(dst, ' ')
+    return dt.AppendClock(dst)
+}
+
+// AppendDateDMY добавляет к dst дату в формате "02.01.2006".
+func (dt DateTime) AppendDateDMY(dst []byte) []byte {
+    dst = append2(dst, dt.Day)
+    dst = append(dst, '.')
+    dst = append2(dst, uint8(dt.Month))
+    dst = append(dst, '.')
+    dst = append2(dst, uint8(dt.Year/100))
+    return append2(dst, uint8(dt.Year%100))
+}
+
+// append2 добавляет к dst число 0-99 двумя десятичными цифрами.
+func append2(dst []byte, v uint8) []byte {
+    return append(dst, '0'+v/10, '0'+v%10)
+}
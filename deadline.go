@@ -0,0 +1,121 @@
+package ds1302
+
+import (
+    "encoding/binary"
+    "errors"
+    "time"
+)
+
+// Ошибки работы со сроками.
+var (
+    ErrNoDeadline        = errors.New("ds1302: deadline is not set")
+    ErrInvalidDeadlineID = errors.New("ds1302: deadline id out of range")
+)
+
+// DeadlineSize - число байт ОЗУ на один срок (упакованное время, см. Pack).
+const DeadlineSize = PackedSize
+
+// Deadlines хранит в резервном ОЗУ набор сроков - окончание лицензии,
+// дату поверки, "заменить фильтр до" - так что они переживают
+// перезагрузки без внешней EEPROM. Сроки адресуются номером 0..n-1
+// и сравниваются с временем RTC.
+type Deadlines struct {
+    dev  *DS1302
+    addr uint8
+    n    uint8
+}
+
+// NewDeadlines создает n сроков в ОЗУ начиная с адреса addr
+// (n*DeadlineSize байт, в 31 байт помещается до 7 сроков). Если область
+// не помещается в ОЗУ, методы возвращают ErrRAMOutOfRange.
+func NewDeadlines(d *DS1302, addr uint8, n uint8) *Deadlines {
+    return &Deadlines{dev: d, addr: addr, n: n}
+}
+
+// SetDeadline сохраняет срок t под номером id.
+func (l *Deadlines) SetDeadline(id uint8, t time.Time) error {
+    secs := Pack(t)
+    if secs == 0 {
+        // Ноль означает "срок не задан".
+        secs = 1
+    }
+    return l.store(id, secs)
+}
+
+// ClearDeadline удаляет срок id.
+func (l *Deadlines) ClearDeadline(id uint8) error {
+    return l.store(id, 0)
+}
+
+// Deadline возвращает срок id или ErrNoDeadline, если он не задан.
+func (l *Deadlines) Deadline(id uint8) (time.Time, error) {
+    l.dev.mu.Lock()
+    defer l.dev.mu.Unlock()
+    return l.deadline(id)
+}
+
+func (l *Deadlines) deadline(id uint8) (time.Time, error) {
+    addr, err := l.offset(id)
+    if err != nil {
+        return time.Time{}, err
+    }
+    var buf [DeadlineSize]byte
+    if err := l.dev.readRAMBytes(addr, buf[:]); err != nil {
+        return time.Time{}, err
+    }
+    secs := binary.LittleEndian.Uint32(buf[:])
+    if secs == 0 {
+        return time.Time{}, ErrNoDeadline
+    }
+    return Unpack(secs), nil
+}
+
+// Remaining возвращает время до срока id по часам RTC.
+// Для истекшего срока значение отрицательное.
+func (l *Deadlines) Remaining(id uint8) (time.Duration, error) {
+    l.dev.mu.Lock()
+    defer l.dev.mu.Unlock()
+    t, err := l.deadline(id)
+    if err != nil {
+        return 0, err
+    }
+    now, err := l.dev.readTime()
+    if err != nil {
+        return 0, err
+    }
+    return t.Sub(now), nil
+}
+
+// Expired сообщает, наступил ли срок id.
+func (l *Deadlines) Expired(id uint8) (bool, error) {
+    r, err := l.Remaining(id)
+    if err != nil {
+        return false, err
+    }
+    return r <= 0, nil
+}
+
+func (l *Deadlines) store(id uint8, secs uint32) error {
+    l.dev.mu.Lock()
+    defer l.dev.mu.Unlock()
+    addr, err := l.offset(id)
+    if err != nil {
+        return err
+    }
+    var buf [DeadlineSize]byte
+    binary.LittleEndian.PutUint32(buf[:], secs)
+    return l.dev.writeRAMBytes(addr, buf[:])
+}
+
+// offset проверяет номер id и область сроков и возвращает адрес срока.
+// Адреса считаются в int, чтобы большой номер не заворачивался
+// на начало области.
+func (l *Deadlines) offset(id uint8) (uint8, error) {
+    if id >= l.n {
+        return 0, l.dev.fail(ErrInvalidDeadlineID)
+    }
+    if int(l.addr)+int(l.n)*DeadlineSize > RAMSize {
+        return 0, l.dev.fail(ErrRAMOutOfRange)
+    }
+    return uint8(int(l.addr) + int(id)*DeadlineSize), nil
+}
@@ -0,0 +1,15 @@
+//go:build !tinygo
+
+package ds1302
+
+import (
+    "time"
+)
+
+// defaultDelay выдерживает паузу в cycles микросекунд через time.Sleep.
+func defaultDelay(cycles uint32) {
+    time.Sleep(time.Duration(cycles) * time.Microsecond)
+}
+
+// calibrateDelay не нужна: time.Sleep не зависит от частоты ядра.
+func calibrateDelay() {}
@@ -0,0 +1,16 @@
+//go:build tinygo && nrf
+
+package ds1302
+
+import (
+    "device/arm"
+)
+
+// spin выполняет n итераций пустого цикла. На nRF5x системный таймер
+// работает от кварца 32768 Гц, и без активного ожидания time.Sleep на
+// микросекунду длился бы не меньше ~30 мкс.
+func spin(n uint32) {
+    for i := uint32(0); i < n; i++ {
+        arm.Asm("nop")
+    }
+}
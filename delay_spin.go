@@ -0,0 +1,17 @@
+//go:build tinygo && !nrf
+
+package ds1302
+
+import (
+    "runtime/volatile"
+)
+
+// spinSink не дает компилятору выбросить цикл spin.
+var spinSink uint32
+
+// spin выполняет n итераций пустого цикла.
+func spin(n uint32) {
+    for i := uint32(0); i < n; i++ {
+        volatile.StoreUint32(&spinSink, i)
+    }
+}
@@ -0,0 +1,63 @@
+//go:build tinygo
+
+package ds1302
+
+import (
+    "time"
+)
+
+// spinsPerMicro - число итераций spin на микросекунду, измеренное
+// calibrateDelay; 0 - калибровка еще не выполнялась.
+var spinsPerMicro uint32
+
+// calibrateTime - минимальная длительность замера. Тик системного таймера
+// бывает грубым (около 30 мкс у nRF), поэтому замер длится достаточно долго,
+// чтобы ошибка округления была в пределах процентов.
+const calibrateTime = 2 * time.Millisecond
+
+// defaultDelay выдерживает паузу в cycles микросекунд активным ожиданием.
+//
+// В TinyGo time.Sleep передает управление планировщику, и пауза в микросекунду
+// длится десятки микросекунд: чтение времени на ESP32 растягивалось до
+// миллисекунд. Число итераций цикла берется из калибровки при Init, поэтому
+// одна и та же прошивка выдерживает времена DS1302 на ядре 48 и 240 МГц.
+// До калибровки пауза отмеряется опросом монотонных часов.
+func defaultDelay(cycles uint32) {
+    if n := spinsPerMicro; n != 0 {
+        spin(cycles * n)
+        return
+    }
+    d := time.Duration(cycles) * time.Microsecond
+    start := time.Now()
+    for time.Since(start) < d {
+    }
+}
+
+// calibrateDelay измеряет скорость цикла spin по системному таймеру.
+// Выполняется один раз на все устройства: частота ядра общая. Результат
+// округляется вверх с запасом в четверть, чтобы пауза не оказалась короче
+// заданной из-за погрешности замера или прерываний во время него.
+func calibrateDelay() {
+    if spinsPerMicro != 0 {
+        return
+    }
+    n := uint32(1000)
+    for {
+        start := time.Now()
+        spin(n)
+        elapsed := time.Since(start)
+        if elapsed >= calibrateTime || n >= 1<<30 {
+            us := uint64(elapsed / time.Microsecond)
+            if us == 0 {
+                us = 1
+            }
+            per := (uint64(n)*5/4 + us - 1) / us
+            if per == 0 {
+                per = 1
+            }
+            spinsPerMicro = uint32(per)
+            return
+        }
+        n *= 2
+    }
+}
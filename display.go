@@ -0,0 +1,37 @@
+package ds1302
+
+import (
+    "github.com/golangworker/ds1302-driver/bcd"
+)
+
+// ClockDigits возвращает цифры времени HHMMSS (каждая 0-9) в порядке
+// вывода слева направо, готовые для 7-сегментного индикатора.
+func (dt DateTime) ClockDigits() [6]uint8 {
+    return [6]uint8{
+        dt.Hour / 10, dt.Hour % 10,
+        dt.Minute / 10, dt.Minute % 10,
+        dt.Second / 10, dt.Second % 10,
+    }
+}
+
+// HourMinuteDigits возвращает цифры HHMM для 4-разрядных индикаторов
+// (например, HT16K33 с двоеточием посередине).
+func (dt DateTime) HourMinuteDigits() [4]uint8 {
+    return [4]uint8{
+        dt.Hour / 10, dt.Hour % 10,
+        dt.Minute / 10, dt.Minute % 10,
+    }
+}
+
+// ClockBCD возвращает часы, минуты и секунды в упакованном BCD
+// (0x23, 0x59, 0x59) - по байту на пару разрядов, как их ждут
+// драйверы индикаторов с BCD-декодером.
+func (dt DateTime) ClockBCD() [3]uint8 {
+    return [3]uint8{bcd.FromDec(dt.Hour), bcd.FromDec(dt.Minute), bcd.FromDec(dt.Second)}
+}
+
+// DateBCD возвращает день, месяц и год (две последние цифры)
+// в упакованном BCD.
+func (dt DateTime) DateBCD() [3]uint8 {
+    return [3]uint8{bcd.FromDec(dt.Day), bcd.FromDec(uint8(dt.Month)), bcd.FromDec(uint8(dt.Year % 100))}
+}
@@ -0,0 +1,19 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// DriftSince сравнивает время RTC с эталоном reference и возвращает
+// знаковое смещение: положительное, если часы спешат, отрицательное,
+// если отстают. reference должен быть получен непосредственно перед
+// вызовом. Разрешение DS1302 - одна секунда, поэтому для точности
+// лучше секунды вызывайте DriftSince сразу после WaitForSecondEdge.
+// При недопустимых значениях в регистрах возвращается ErrInvalidBCD.
+func (d *DS1302) DriftSince(reference time.Time) (time.Duration, error) {
+    t, err := d.now()
+    if err != nil {
+        return 0, err
+    }
+    return t.Sub(reference), nil
+}
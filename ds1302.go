@@ -1,5 +1,3 @@
-//go:build tinygo
-
 // Package ds1302 предоставляет драйвер для микросхемы DS1302 Real Time Clock (RTC)
 // для использования с TinyGo на микроконтроллерах ESP32.
 //
@@ -8,6 +6,10 @@
 // Дата автоматически корректируется для месяцев с менее чем 31 днем,
 // включая коррекцию для високосного года.
 //
+// Протокол обмена реализован поверх интерфейса Pin, поэтому драйвер
+// также работает на одноплатных компьютерах через адаптеры GPIO
+// (см. New). Под TinyGo используйте NewDS1302 с пинами machine.Pin.
+//
 // Пример использования:
 //
 //     import "github.com/golangworker/ds1302-driver"
@@ -20,13 +22,18 @@
 package ds1302
 
 import (
-    "machine"
+    "context"
+    "sync"
     "time"
+
+    "github.com/golangworker/ds1302-driver/bcd"
+    "github.com/golangworker/ds1302-driver/codec"
 )
 
 // Регистры DS1302 для записи и чтения времени.
 // DS1302 использует отдельные адреса для операций чтения и записи.
 // Младший бит адреса определяет операцию: 0 - запись, 1 - чтение.
+// В новом коде предпочтительнее типизированные команды (см. Command).
 const (
     DS1302_SECONDS_WRITE = 0x80 // Регистр записи секунд (0-59)
     DS1302_SECONDS_READ  = 0x81 // Регистр чтения секунд (0-59)
@@ -44,6 +51,32 @@ const (
     DS1302_YEAR_READ     = 0x8D // Регистр чтения года (00-99, представляет 2000-2099)
     DS1302_WP_WRITE      = 0x8E // Регистр записи защиты от записи (0x00 - разрешить, 0x80 - запретить)
     DS1302_WP_READ       = 0x8F // Регистр чтения защиты от записи
+    DS1302_TRICKLE_WRITE = 0x90 // Регистр записи настроек подзарядки (trickle charger)
+    DS1302_TRICKLE_READ  = 0x91 // Регистр чтения настроек подзарядки
+)
+
+// Команды пакетного (burst) обмена. Пакет часов содержит 8 байт в порядке:
+// секунды, минуты, часы, дата, месяц, день недели, год, защита от записи.
+const (
+    DS1302_CLOCK_BURST_WRITE = 0xBE // Пакетная запись регистров часов
+    DS1302_CLOCK_BURST_READ  = 0xBF // Пакетное чтение регистров часов
+)
+
+// Биты регистров часов.
+const (
+    DS1302_CH_BIT  = 0x80 // Бит CH регистра секунд: генератор остановлен
+    DS1302_12H_BIT = 0x80 // Бит 12/24 регистра часов: 12-часовой формат
+    DS1302_PM_BIT  = 0x20 // Бит AM/PM регистра часов в 12-часовом формате
+    DS1302_WP_BIT  = 0x80 // Бит WP регистра защиты от записи
+)
+
+// Адреса резервного ОЗУ DS1302.
+// Микросхема содержит 31 байт статического ОЗУ, которое сохраняется
+// при питании от резервной батареи (размер задан константой RAMSize).
+// Адрес n-го байта получается прибавлением 2*n к базовому адресу записи или чтения.
+const (
+    DS1302_RAM_WRITE = 0xC0 // Адрес записи нулевого байта ОЗУ (0xC0, 0xC2, ... 0xFC)
+    DS1302_RAM_READ  = 0xC1 // Адрес чтения нулевого байта ОЗУ (0xC1, 0xC3, ... 0xFD)
 )
 
 // DS1302 представляет драйвер для микросхемы DS1302 Real Time Clock.
@@ -57,13 +90,35 @@ const (
 // DS1302 использует последовательный протокол передачи данных,
 // где каждый байт передается младшими битами вперед (LSB first).
 type DS1302 struct {
-    clk machine.Pin  // CLK (Serial Clock) - тактовый сигнал
-    dat machine.Pin  // DAT (Serial Data) - линия передачи данных
-    rst machine.Pin  // RST (Reset) - сигнал выбора микросхемы
+    clk Pin  // CLK (Serial Clock) - тактовый сигнал
+    dat Pin  // DAT (Serial Data) - линия передачи данных
+    rst Pin  // RST (Reset) - сигнал выбора микросхемы
+    
+    mu             sync.Mutex    // Защищает обмен по шине и состояние драйвера
+    cfg            Config        // Необязательные параметры (см. Configure)
+    metrics        Metrics       // Счетчики состояния (см. Metrics)
+    counters       Counters      // Счетчики обмена (см. Counters)
+    ramUsage       *RAMUsage     // Учет записей в ОЗУ или nil (см. TrackRAMWrites)
+    guardBackwards bool          // Запрет перевода часов назад (см. GuardBackwards)
+    audit          *auditLog     // Журнал перестановок в ОЗУ или nil (см. EnableAudit)
+    localAddr      uint8         // Адрес местного смещения в ОЗУ (см. EnableLocalOffset)
+    localEnabled   bool
+    lastSet        time.Time     // Время RTC последней установки или сверки (см. Trust)
+    powerLost      bool          // Замечена остановка генератора после последней установки
+    setSeq         uint32        // Число установок и сверок часов через драйвер
+    trustMaxAge    time.Duration // См. SetTrustMaxAge
+    tsAnchor       uint32        // Время Unix последнего чтения для Timestamp
+    tsMono         time.Time     // Момент этого чтения по часам МК
+    closed         bool          // Вызван Close; линии не трогаются до Init
+    pinErr         error         // Линии назначены неверно (см. PinError)
+    inTransfer     bool          // RST поднят: обмен начат и не завершен (см. begin)
+    bus            BusLocker     // Взятый замок Config.BusLocker или nil
+    onTimeSet      []timeSetFunc // Подписчики OnTimeSet
+    timeSets       []timeSet     // Записи времени, ожидающие уведомления (см. unlock)
 }
 
-// NewDS1302 создает новый экземпляр DS1302
-func NewDS1302(clk, dat, rst machine.Pin) *DS1302 {
+// New создает новый экземпляр DS1302 поверх произвольной реализации Pin
+func New(clk, dat, rst Pin) *DS1302 {
     return &DS1302{
         clk: clk,
         dat: dat,
@@ -71,20 +126,93 @@ func NewDS1302(clk, dat, rst machine.Pin) *DS1302 {
     }
 }
 
-// Init инициализирует DS1302
-func (d *DS1302) Init() {
-    d.clk.Configure(machine.PinConfig{Mode: machine.PinOutput})
-    d.dat.Configure(machine.PinConfig{Mode: machine.PinOutput})
-    d.rst.Configure(machine.PinConfig{Mode: machine.PinOutput})
+// Init инициализирует DS1302. После Close снова делает устройство рабочим.
+// Если генератор остановлен, запускает его (см. Config.NoAutoStart).
+// В TinyGo при первом вызове калибрует паузы между фронтами CLK
+// под частоту ядра (около 2 мс), если не задана Config.DelayFn.
+// Для устройства с неверно назначенными линиями возвращает *PinError
+// и не трогает линии.
+func (d *DS1302) Init() error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if d.pinErr != nil {
+        return d.fail(d.pinErr)
+    }
+    if d.cfg.DelayFn == nil {
+        calibrateDelay()
+    }
+    d.init()
+    if !d.cfg.NoAutoStart {
+        // Остановленный генератор - единственный признак пропадания
+        // питания: запоминаем его для Trust до запуска часов.
+        if d.readRegister(DS1302_SECONDS_READ)&DS1302_CH_BIT != 0 {
+            d.powerLost = true
+        }
+        d.setRunning(true)
+    }
+    return nil
+}
+
+// SetRunning запускает или останавливает генератор (бит CH), сохраняя
+// значение секунд. Остановленные часы не считают время и меньше расходуют
+// батарею.
+func (d *DS1302) SetRunning(run bool) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    d.setRunning(run)
+    return nil
+}
+
+// setRunning меняет бит CH, если он отличается от нужного.
+func (d *DS1302) setRunning(run bool) {
+    sec := d.readRegister(DS1302_SECONDS_READ)
+    want := sec &^ DS1302_CH_BIT
+    if !run {
+        want |= DS1302_CH_BIT
+    }
+    if want == sec {
+        return
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    d.writeRegister(DS1302_SECONDS_WRITE, want)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+}
+
+// init переводит линии в исходное состояние.
+func (d *DS1302) init() {
+    d.closed = false
+    d.lockBus()
+    defer d.unlockBus()
+    d.clk.ConfigureOutput()
+    d.dat.ConfigureOutput()
+    d.rst.ConfigureOutput()
     
     d.clk.Low()
     d.rst.Low()
     d.dat.Low()
+    d.inTransfer = false
+}
+
+// edgeDelay - пауза между фронтами CLK в микросекундах. С запасом покрывает
+// минимальные времена по документации DS1302 при питании 2 В.
+const edgeDelay = 1
+
+// delay выдерживает паузу в cycles микросекунд функцией Config.DelayFn
+// или, если она не задана, встроенной реализацией для платформы.
+func (d *DS1302) delay(cycles uint32) {
+    if f := d.cfg.DelayFn; f != nil {
+        f(cycles)
+        return
+    }
+    defaultDelay(cycles)
 }
 
 // writeByte записывает байт в DS1302
 func (d *DS1302) writeByte(data uint8) {
-    d.dat.Configure(machine.PinConfig{Mode: machine.PinOutput})
+    d.dat.ConfigureOutput()
     
     for i := 0; i < 8; i++ {
         if data&(1<<i) != 0 {
@@ -93,83 +221,289 @@ func (d *DS1302) writeByte(data uint8) {
             d.dat.Low()
         }
         d.clk.High()
-        time.Sleep(time.Microsecond)
+        d.delay(edgeDelay)
         d.clk.Low()
-        time.Sleep(time.Microsecond)
+        d.delay(edgeDelay)
     }
 }
 
-// readByte читает байт из DS1302
-func (d *DS1302) readByte() uint8 {
-    var data uint8
-    d.dat.Configure(machine.PinConfig{Mode: machine.PinInput})
+// readByte читает байт из DS1302. stable ложно, если при Config.DoubleSample
+// выборки какого-либо бита разошлись.
+func (d *DS1302) readByte() (data uint8, stable bool) {
+    stable = true
+    d.dat.ConfigureInput()
     
     for i := 0; i < 8; i++ {
         d.clk.High()
-        time.Sleep(time.Microsecond)
-        if d.dat.Get() {
+        d.delay(edgeDelay)
+        bit, ok := d.sampleBit()
+        if bit {
             data |= (1 << i)
         }
+        stable = stable && ok
         d.clk.Low()
-        time.Sleep(time.Microsecond)
+        d.delay(edgeDelay)
     }
-    return data
+    return data, stable
 }
 
 // writeRegister записывает в регистр DS1302
 func (d *DS1302) writeRegister(reg, value uint8) {
-    d.rst.High()  // Начать передачу
+    if d.closed {
+        return
+    }
+    d.metrics.Transactions++
+    d.counters.Writes++
+    d.counters.Bytes += 2
+    d.countRAMWrite(reg)
+    if l := d.cfg.Logger; l != nil {
+        l.Debugf("ds1302: write %#02x <- %#02x", reg, value)
+    }
+    d.begin()     // Начать передачу
     d.writeByte(reg)
     d.writeByte(value)
-    d.rst.Low()   // Закончить передачу
+    d.end()       // Закончить передачу
 }
 
 // readRegister читает из регистра DS1302
 func (d *DS1302) readRegister(reg uint8) uint8 {
-    d.rst.High()  // Начать передачу
-    d.writeByte(reg)
-    value := d.readByte()
-    d.rst.Low()   // Закончить передачу
+    if d.closed {
+        return 0
+    }
+    d.metrics.Transactions++
+    d.counters.Reads++
+    d.counters.Bytes += 2
+    var value uint8
+    for attempt := 0; ; attempt++ {
+        var stable bool
+        d.begin()     // Начать передачу
+        d.writeByte(reg)
+        value, stable = d.readByte()
+        d.end()       // Закончить передачу
+        if !d.retryRead(attempt, stable) {
+            break
+        }
+    }
+    if l := d.cfg.Logger; l != nil {
+        l.Debugf("ds1302: read %#02x -> %#02x", reg, value)
+    }
     return value
 }
 
-// bcdToDec конвертирует BCD в десятичное
-func bcdToDec(bcd uint8) uint8 {
-    return ((bcd >> 4) * 10) + (bcd & 0x0F)
+// readBurst читает len(buf) байт пакетным чтением по команде cmd
+func (d *DS1302) readBurst(cmd Command, buf []byte) {
+    d.readBurstContext(context.Background(), cmd, buf)
+}
+
+// ReadRegister читает сырое значение регистра DS1302 по команде чтения cmd
+// (например, ClockRead(RegTrickle)).
+// Предназначен для отладки и инструментов; для работы со временем используйте ReadTime.
+// Адрес записи, пакетная команда или несуществующий регистр возвращают ошибку.
+func (d *DS1302) ReadRegister(cmd Command) (uint8, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return 0, err
+    }
+    if err := cmd.validate(true); err != nil {
+        return 0, d.fail(err)
+    }
+    return d.readRegister(uint8(cmd)), nil
+}
+
+// WriteRegister записывает сырое значение в регистр DS1302 по команде записи cmd
+// (например, ClockWrite(RegTrickle)).
+// Защита от записи не снимается автоматически (см. DS1302_WP_WRITE).
+// Адрес чтения, пакетная команда или несуществующий регистр возвращают ошибку.
+func (d *DS1302) WriteRegister(cmd Command, value uint8) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    if err := cmd.validate(false); err != nil {
+        return d.fail(err)
+    }
+    d.writeRegister(uint8(cmd), value)
+    return nil
 }
 
-// decToBcd конвертирует десятичное в BCD
-func decToBcd(dec uint8) uint8 {
-    return ((dec / 10) << 4) + (dec % 10)
+// SetTime устанавливает время в DS1302.
+// Если включена GuardBackwards, перевод часов назад отклоняется
+// с ErrTimeBackwards (см. ForceSetTime).
+func (d *DS1302) SetTime(t time.Time) error {
+    d.mu.Lock()
+    defer d.unlock()
+    return d.setTimeFrom(t, AdjustManual)
 }
 
-// SetTime устанавливает время в DS1302
-func (d *DS1302) SetTime(t time.Time) {
+// ForceSetTime устанавливает время в DS1302 без проверки GuardBackwards.
+func (d *DS1302) ForceSetTime(t time.Time) error {
+    d.mu.Lock()
+    defer d.unlock()
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    t, err := d.clockTime(t)
+    if err != nil {
+        return err
+    }
     // Отключить защиту от записи
     d.writeRegister(DS1302_WP_WRITE, 0x00)
     
-    // Записать время
-    d.writeRegister(DS1302_SECONDS_WRITE, decToBcd(uint8(t.Second())))
-    d.writeRegister(DS1302_MINUTES_WRITE, decToBcd(uint8(t.Minute())))
-    d.writeRegister(DS1302_HOURS_WRITE, decToBcd(uint8(t.Hour())))
-    d.writeRegister(DS1302_DATE_WRITE, decToBcd(uint8(t.Day())))
-    d.writeRegister(DS1302_MONTH_WRITE, decToBcd(uint8(t.Month())))
-    d.writeRegister(DS1302_YEAR_WRITE, decToBcd(uint8(t.Year()-2000)))
+    err = d.writeTimeAudited(t, AdjustForced)
     
     // Включить защиту от записи
     d.writeRegister(DS1302_WP_WRITE, 0x80)
+    return err
+}
+
+// writeTime записывает время в регистры часов, сохраняя текущий формат часов (12/24).
+// Защита от записи должна быть снята вызывающим кодом.
+func (d *DS1302) writeTime(t time.Time) {
+    d.writeClock(t, codec.HourModeOf(d.readRegister(DS1302_HOURS_READ)))
 }
 
-// ReadTime читает время из DS1302
+// writeClock записывает время в регистры часов в формате mode.
+//
+// Регистры пишутся по одному, поэтому на время записи генератор
+// останавливается (секунды с битом CH), а секунды записываются последними
+// и снова запускают счет. Иначе перенос в микросхеме между записями
+// (59 -> 00 секунд с увеличением минут) затирался бы следующей записью
+// и часы отставали бы почти на минуту.
+func (d *DS1302) writeClock(t time.Time, mode HourMode) {
+    d.writeRegister(DS1302_SECONDS_WRITE, DS1302_CH_BIT)
+    d.writeDate(t, mode)
+    d.writeRegister(DS1302_SECONDS_WRITE, bcd.FromDec(uint8(t.Second())))
+}
+
+// writeDate записывает все регистры времени, кроме секунд.
+// Генератор должен быть остановлен вызывающим кодом.
+func (d *DS1302) writeDate(t time.Time, mode HourMode) {
+    r := codec.Encode(t, mode, d.cfg.SundayFirst)
+    d.writeRegister(DS1302_MINUTES_WRITE, r.Minutes)
+    d.writeRegister(DS1302_HOURS_WRITE, r.Hours)
+    d.writeRegister(DS1302_DATE_WRITE, r.Date)
+    d.writeRegister(DS1302_MONTH_WRITE, r.Month)
+    d.writeRegister(DS1302_DAY_WRITE, r.Weekday)
+    d.writeRegister(DS1302_YEAR_WRITE, r.Year)
+}
+
+// ReadTime читает время из DS1302.
+// Недопустимые BCD значения учитываются в Metrics как ошибки проверки.
 func (d *DS1302) ReadTime() time.Time {
-    seconds := bcdToDec(d.readRegister(DS1302_SECONDS_READ) & 0x7F)
-    minutes := bcdToDec(d.readRegister(DS1302_MINUTES_READ))
-    hours := bcdToDec(d.readRegister(DS1302_HOURS_READ))
-    day := bcdToDec(d.readRegister(DS1302_DATE_READ))
-    month := bcdToDec(d.readRegister(DS1302_MONTH_READ))
-    year := int(2000) + int(bcdToDec(d.readRegister(DS1302_YEAR_READ)))
+    t, _ := d.now()
+    return t
+}
+
+// now читает время под блокировкой устройства. Используется помощниками,
+// которым нужен только один обмен с часами.
+func (d *DS1302) now() (time.Time, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.readTime()
+}
+
+// readTime читает время из DS1302 и возвращает ошибку проверки BCD,
+// уже учтенную в Metrics.
+func (d *DS1302) readTime() (time.Time, error) {
+    if err := d.checkOpen(); err != nil {
+        return time.Time{}, err
+    }
+    t, err := decodeTime(d.readTimeRegs())
+    return t, d.fail(err)
+}
+
+// readTimeRegs читает регистры секунд, минут, часов, даты, месяца и года
+// одним пакетным чтением до регистра года: 8 байт на шине вместо 12
+// у отдельных чтений, и микросхема отдает согласованный снимок без переноса
+// между регистрами.
+func (d *DS1302) readTimeRegs() [6]uint8 {
+    var b [7]uint8
+    d.readBurst(ClockBurstRead, b[:])
+    return [6]uint8{b[0], b[1], b[2], b[3], b[4], b[6]}
+}
+
+// decodeTime преобразует регистры секунд, минут, часов, даты, месяца и года
+// в time.Time (см. codec.Decode).
+func decodeTime(regs [6]uint8) (time.Time, error) {
+    return codec.Decode(codec.Regs{
+        Seconds: regs[0], Minutes: regs[1], Hours: regs[2],
+        Date: regs[3], Month: regs[4], Year: regs[5],
+    })
+}
+
+// ReadRAM читает байт резервного ОЗУ по адресу addr (0-30)
+func (d *DS1302) ReadRAM(addr uint8) (uint8, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.readRAM(addr)
+}
+
+func (d *DS1302) readRAM(addr uint8) (uint8, error) {
+    if err := d.checkOpen(); err != nil {
+        return 0, err
+    }
+    if addr >= RAMSize {
+        return 0, d.fail(ErrRAMOutOfRange)
+    }
+    return d.readRegister(DS1302_RAM_READ + addr*2), nil
+}
+
+// ReadRAMBytes читает len(buf) байт резервного ОЗУ начиная с адреса addr
+func (d *DS1302) ReadRAMBytes(addr uint8, buf []byte) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.readRAMBytes(addr, buf)
+}
+
+func (d *DS1302) readRAMBytes(addr uint8, buf []byte) error {
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    if int(addr)+len(buf) > RAMSize {
+        return d.fail(ErrRAMOutOfRange)
+    }
+    for i := range buf {
+        buf[i] = d.readRegister(DS1302_RAM_READ + (addr+uint8(i))*2)
+    }
+    return nil
+}
+
+// WriteRAM записывает байт в резервное ОЗУ по адресу addr (0-30)
+func (d *DS1302) WriteRAM(addr, value uint8) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.writeRAMBytes(addr, []byte{value})
+}
+
+// WriteRAMBytes записывает несколько байт в резервное ОЗУ начиная с адреса addr.
+// Защита от записи снимается один раз на всю операцию.
+func (d *DS1302) WriteRAMBytes(addr uint8, data []byte) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.writeRAMBytes(addr, data)
+}
+
+// writeRAMBytes проверяет границы и записывает data в ОЗУ, снимая защиту от записи.
+func (d *DS1302) writeRAMBytes(addr uint8, data []byte) error {
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    if int(addr)+len(data) > RAMSize {
+        return d.fail(ErrRAMOutOfRange)
+    }
     
-    return time.Date(int(year), time.Month(month), int(day), 
-                    int(hours), int(minutes), int(seconds), 0, time.UTC)
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    d.writeRAM(addr, data)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    return nil
 }
 
+// writeRAM записывает байты в ОЗУ без проверки границ.
+// Защита от записи должна быть снята вызывающим кодом.
+func (d *DS1302) writeRAM(addr uint8, data []byte) {
+    for i, b := range data {
+        d.writeRegister(DS1302_RAM_WRITE+(addr+uint8(i))*2, b)
+    }
+}
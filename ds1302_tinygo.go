@@ -0,0 +1,71 @@
+//go:build tinygo
+
+package ds1302
+
+import (
+    "machine"
+)
+
+// machinePin адаптирует machine.Pin к интерфейсу Pin.
+type machinePin struct {
+    machine.Pin
+}
+
+func (p machinePin) ConfigureOutput() {
+    p.Configure(machine.PinConfig{Mode: machine.PinOutput})
+}
+
+func (p machinePin) ConfigureInput() {
+    p.Configure(machine.PinConfig{Mode: machine.PinInput})
+}
+
+// PinOf оборачивает machine.Pin в Pin - для New, когда линии приходят
+// из разных источников, например RST с выхода ShiftRegister:
+//
+//     sr := ds1302.NewShiftRegister(ds1302.PinOf(machine.GP2), ds1302.PinOf(machine.GP3), ds1302.PinOf(machine.GP4), 16)
+//     rtcs := sr.Devices(ds1302.PinOf(machine.GP10), ds1302.PinOf(machine.GP11))
+func PinOf(p machine.Pin) Pin {
+    return machinePin{p}
+}
+
+// NewDS1302 создает новый экземпляр DS1302.
+// Линии проверяются ValidatePins; при ошибке устройство не трогает их,
+// а Init и методы возвращают ошибку в Metrics и результатах (см. PinError).
+func NewDS1302(clk, dat, rst machine.Pin) *DS1302 {
+    d := New(machinePin{clk}, machinePin{dat}, machinePin{rst})
+    if err := ValidatePins(clk, dat, rst); err != nil {
+        d.pinErr = err
+        d.closed = true
+    }
+    return d
+}
+
+// ValidatePins проверяет назначение линий: ни одна не machine.NoPin,
+// линии не совпадают, все три могут работать выходом, а DAT - еще и входом
+// (с учетом ограничений платы, например выводов только для ввода у ESP32).
+// Возвращает *PinError.
+func ValidatePins(clk, dat, rst machine.Pin) error {
+    lines := [3]struct {
+        name  string
+        pin   machine.Pin
+        input bool
+    }{{"CLK", clk, false}, {"DAT", dat, true}, {"RST", rst, false}}
+    for i, l := range lines {
+        if l.pin == machine.NoPin {
+            return &PinError{Line: l.name, Err: ErrNoPin}
+        }
+        for _, prev := range lines[:i] {
+            if prev.pin == l.pin {
+                return &PinError{Line: l.name, Err: ErrDuplicatePin}
+            }
+        }
+        input, output := pinCaps(l.pin)
+        if !output {
+            return &PinError{Line: l.name, Err: ErrPinNotOutput}
+        }
+        if l.input && !input {
+            return &PinError{Line: l.name, Err: ErrPinNotInput}
+        }
+    }
+    return nil
+}
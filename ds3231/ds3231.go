@@ -0,0 +1,176 @@
+// Package ds3231 повторяет набор методов драйвера tinygo.org/x/drivers/ds3231
+// поверх DS1302, чтобы проекты, перешедшие на более дешевую микросхему,
+// не меняли прикладной код: достаточно заменить импорт и конструктор.
+//
+//     // было: rtc := ds3231.New(machine.I2C0)
+//     rtc := ds3231.New(ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5))
+//     rtc.Configure()
+//     t, err := rtc.ReadTime()
+//
+// У DS1302 нет датчика температуры, будильников, выхода SQW и выхода 32 кГц.
+// Методы, которые их включают или читают, возвращают ErrNotSupported,
+// а запросы выключить несуществующее (SQW_OFF, SetEnabledAlarm1(false),
+// ClearAlarm1 и т.п.) выполняются без ошибки. Для будильников на DS1302
+// используйте ds1302.Scheduler. Карта регистров DS3231 (REG_* и биты) не
+// повторяется: она не имеет смысла для другой микросхемы.
+package ds3231
+
+import (
+    "errors"
+    "time"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+)
+
+// ErrNotSupported возвращается для возможностей DS3231, которых нет у DS1302.
+var ErrNotSupported = errors.New("ds3231: not supported by DS1302")
+
+// Address - адрес DS3231 на шине I2C; сохранен для совместимости.
+const Address = 0x68
+
+// Device - часы DS1302 с методами драйвера DS3231.
+type Device struct {
+    RTC *ds1302.DS1302
+
+    // Address сохранен для совместимости с ds3231.Device и не используется.
+    Address uint16
+}
+
+// New создает адаптер для rtc.
+func New(rtc *ds1302.DS1302) Device {
+    return Device{RTC: rtc, Address: Address}
+}
+
+// Configure инициализирует линии DS1302 (см. ds1302.DS1302.Init).
+// Возвращает false, если линии назначены неверно.
+func (d *Device) Configure() bool {
+    return d.RTC.Init() == nil
+}
+
+// IsTimeValid сообщает, что время правдоподобно. Флагу OSF у DS3231
+// соответствует проверка ds1302.DS1302.IsTimeValid: генератор работает,
+// регистры допустимы и часы не в состоянии после сброса.
+func (d *Device) IsTimeValid() bool {
+    ok, _ := d.RTC.IsTimeValid()
+    return ok
+}
+
+// IsRunning сообщает, что генератор работает.
+func (d *Device) IsRunning() bool {
+    st, err := d.RTC.Status()
+    return err == nil && st.Running
+}
+
+// SetRunning запускает или останавливает генератор.
+func (d *Device) SetRunning(isRunning bool) error {
+    return d.RTC.SetRunning(isRunning)
+}
+
+// SetTime устанавливает время.
+func (d *Device) SetTime(dt time.Time) error {
+    return d.RTC.SetTime(dt)
+}
+
+// ReadTime читает время. Недопустимые значения регистров возвращают ошибку.
+func (d *Device) ReadTime() (dt time.Time, err error) {
+    st, err := d.RTC.Status()
+    return st.Time, err
+}
+
+// ReadTemperature возвращает ErrNotSupported: у DS1302 нет датчика температуры.
+func (d *Device) ReadTemperature() (int32, error) {
+    return 0, ErrNotSupported
+}
+
+// GetSqwPinMode возвращает SQW_OFF: у DS1302 нет выхода SQW.
+func (d *Device) GetSqwPinMode() SqwPinMode {
+    return SQW_OFF
+}
+
+// SetSqwPinMode принимает только SQW_OFF; остальные режимы возвращают
+// ErrNotSupported.
+func (d *Device) SetSqwPinMode(mode SqwPinMode) error {
+    if mode != SQW_OFF {
+        return ErrNotSupported
+    }
+    return nil
+}
+
+// SetAlarm1 возвращает ErrNotSupported: у DS1302 нет будильников.
+func (d *Device) SetAlarm1(dt time.Time, mode Alarm1Mode) error {
+    return ErrNotSupported
+}
+
+// ReadAlarm1 возвращает ErrNotSupported: у DS1302 нет будильников.
+func (d *Device) ReadAlarm1() (dt time.Time, err error) {
+    return time.Time{}, ErrNotSupported
+}
+
+// SetAlarm2 возвращает ErrNotSupported: у DS1302 нет будильников.
+func (d *Device) SetAlarm2(dt time.Time, mode Alarm2Mode) error {
+    return ErrNotSupported
+}
+
+// ReadAlarm2 возвращает ErrNotSupported: у DS1302 нет будильников.
+func (d *Device) ReadAlarm2() (dt time.Time, err error) {
+    return time.Time{}, ErrNotSupported
+}
+
+// IsEnabledAlarm1 всегда возвращает false.
+func (d *Device) IsEnabledAlarm1() bool {
+    return false
+}
+
+// SetEnabledAlarm1 принимает только false; включение возвращает ErrNotSupported.
+func (d *Device) SetEnabledAlarm1(enable bool) error {
+    return unsupported(enable)
+}
+
+// IsEnabledAlarm2 всегда возвращает false.
+func (d *Device) IsEnabledAlarm2() bool {
+    return false
+}
+
+// SetEnabledAlarm2 принимает только false; включение возвращает ErrNotSupported.
+func (d *Device) SetEnabledAlarm2(enable bool) error {
+    return unsupported(enable)
+}
+
+// ClearAlarm1 ничего не делает: будильник 1 никогда не срабатывает.
+func (d *Device) ClearAlarm1() error {
+    return nil
+}
+
+// ClearAlarm2 ничего не делает: будильник 2 никогда не срабатывает.
+func (d *Device) ClearAlarm2() error {
+    return nil
+}
+
+// IsAlarm1Fired всегда возвращает false.
+func (d *Device) IsAlarm1Fired() bool {
+    return false
+}
+
+// IsAlarm2Fired всегда возвращает false.
+func (d *Device) IsAlarm2Fired() bool {
+    return false
+}
+
+// SetEnabled32K принимает только false: у DS1302 нет выхода 32 кГц.
+func (d *Device) SetEnabled32K(enable bool) error {
+    return unsupported(enable)
+}
+
+// IsEnabled32K всегда возвращает false.
+func (d *Device) IsEnabled32K() bool {
+    return false
+}
+
+// unsupported возвращает ErrNotSupported при попытке включить возможность,
+// которой нет у DS1302; выключение проходит без ошибки.
+func unsupported(enable bool) error {
+    if enable {
+        return ErrNotSupported
+    }
+    return nil
+}
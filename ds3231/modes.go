@@ -0,0 +1,50 @@
+package ds3231
+
+// Mode повторяет ds3231.Mode; сохранен для совместимости и не используется.
+type Mode uint8
+
+const (
+    None          Mode = 0
+    BatteryBackup Mode = 1
+    Clock         Mode = 2
+    AlarmOne      Mode = 3
+    AlarmTwo      Mode = 4
+    ModeAlarmBoth Mode = 5
+)
+
+// SqwPinMode - частота на выходе SQW. У DS1302 выхода нет, поэтому
+// поддерживается только SQW_OFF.
+type SqwPinMode uint8
+
+const (
+    SQW_OFF  SqwPinMode = 0x1C
+    SQW_1HZ  SqwPinMode = 0x00
+    SQW_1KHZ SqwPinMode = 0x08
+    SQW_4KHZ SqwPinMode = 0x10
+    SQW_8KHZ SqwPinMode = 0x18
+)
+
+// Alarm1Mode определяет, какие поля времени должны совпасть для
+// срабатывания будильника 1. Значения совпадают с драйвером DS3231.
+type Alarm1Mode uint8
+
+const (
+    A1_PER_SECOND Alarm1Mode = 0x0F // Каждую секунду
+    A1_SECOND     Alarm1Mode = 0x0E // Совпали секунды
+    A1_MINUTE     Alarm1Mode = 0x0C // Совпали секунды и минуты
+    A1_HOUR       Alarm1Mode = 0x08 // Совпали секунды, минуты и часы
+    A1_DATE       Alarm1Mode = 0x00 // Совпали секунды, минуты, часы и число месяца
+    A1_DAY        Alarm1Mode = 0x10 // Совпали секунды, минуты, часы и день недели
+)
+
+// Alarm2Mode определяет, какие поля времени должны совпасть для
+// срабатывания будильника 2 (с точностью до минуты).
+type Alarm2Mode uint8
+
+const (
+    A2_PER_MINUTE Alarm2Mode = 0x07 // Каждую минуту
+    A2_MINUTE     Alarm2Mode = 0x06 // Совпали минуты
+    A2_HOUR       Alarm2Mode = 0x04 // Совпали минуты и часы
+    A2_DATE       Alarm2Mode = 0x00 // Совпали минуты, часы и число месяца
+    A2_DAY        Alarm2Mode = 0x08 // Совпали минуты, часы и день недели
+)
@@ -0,0 +1,89 @@
+package ds1302
+
+import (
+    "context"
+    "errors"
+    "time"
+)
+
+// ErrNoSecondEdge возвращается, если регистр секунд не сменился
+// за время ожидания: генератор остановлен или микросхема не отвечает.
+var ErrNoSecondEdge = errors.New("ds1302: seconds register did not advance")
+
+// secondEdgeTimeout - наибольшее время ожидания смены секунды.
+const secondEdgeTimeout = 1100 * time.Millisecond
+
+// secondEdgePoll - пауза между чтениями регистра секунд.
+// Определяет точность обнаружения фронта.
+const secondEdgePoll = 100 * time.Microsecond
+
+// WaitForSecondEdge опрашивает регистр секунд и возвращается сразу после
+// его смены, вместе с новым временем. У DS1302 нет выхода меандра, поэтому
+// это единственный способ получить опорный фронт 1 Гц - например, для
+// выравнивания измерений или мигания секундным индикатором.
+//
+// Блокирует выполнение до ~1,1 с; если секунда не сменилась,
+// возвращает ErrNoSecondEdge.
+//
+// Блокировка устройства берется на каждое чтение, а не на все ожидание,
+// поэтому другие горутины могут работать с часами в это время.
+func (d *DS1302) WaitForSecondEdge() (time.Time, error) {
+    return d.WaitForSecondEdgeContext(context.Background())
+}
+
+// WaitForSecondEdgeContext работает как WaitForSecondEdge, но прекращает
+// ожидание при отмене ctx и возвращает ctx.Err(). Позволяет кооперативным
+// планировщикам ограничить блокировку меньшим сроком, чем ~1,1 с.
+func (d *DS1302) WaitForSecondEdgeContext(ctx context.Context) (time.Time, error) {
+    start, err := d.readSeconds()
+    if err != nil {
+        return time.Time{}, err
+    }
+    deadline := time.Now().Add(secondEdgeTimeout)
+    for {
+        sec, err := d.readSeconds()
+        if err != nil {
+            return time.Time{}, err
+        }
+        if sec != start {
+            break
+        }
+        if err := ctx.Err(); err != nil {
+            return time.Time{}, err
+        }
+        if !time.Now().Before(deadline) {
+            d.mu.Lock()
+            defer d.mu.Unlock()
+            return time.Time{}, d.fail(ErrNoSecondEdge)
+        }
+        time.Sleep(secondEdgePoll)
+    }
+    return d.ReadTime(), nil
+}
+
+// waitSecondEdge ждет смены регистра секунд, не отпуская блокировку
+// устройства, и возвращает новое время и момент смены по часам МК.
+// Вызывается под d.mu.
+func (d *DS1302) waitSecondEdge() (time.Time, time.Time, error) {
+    start := d.readRegister(DS1302_SECONDS_READ)
+    deadline := time.Now().Add(secondEdgeTimeout)
+    for d.readRegister(DS1302_SECONDS_READ) == start {
+        if !time.Now().Before(deadline) {
+            return time.Time{}, time.Time{}, d.fail(ErrNoSecondEdge)
+        }
+        time.Sleep(secondEdgePoll)
+    }
+    edge := time.Now()
+    t, err := d.readTime()
+    return t, edge, err
+}
+
+// readSeconds читает регистр секунд под блокировкой устройства.
+func (d *DS1302) readSeconds() (uint8, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return 0, err
+    }
+    return d.readRegister(DS1302_SECONDS_READ), nil
+}
@@ -6,6 +6,7 @@ import (
 	"machine"
 	"time"
 "github.com/golangworker/ds1302-driver"
+"github.com/golangworker/ds1302-driver/provision"
 )
 
 func main() {
@@ -16,15 +17,16 @@ func main() {
 	// Создаем экземпляр DS1302
 	// CLK -> GPIO18, DAT -> GPIO19, RST -> GPIO5
 	rtc := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5)
-	rtc.Init()
+	if err := rtc.Init(); err != nil {
+		println("DS1302:", err.Error())
+	}
 
-	// Устанавливаем начальное время (только один раз)
-	// В реальном проекте это можно делать через веб-интерфейс или другой способ
-	initialTime := time.Date(2024, 8, 5, 21, 0, 0, 0, time.UTC)
-	rtc.SetTime(initialTime)
+	// Время устанавливается с хоста сразу после прошивки
+	// (из каталога cmd клона репозитория):
+	//   go run ./ds1302sync /dev/ttyUSB0
+	sync := provision.Handler{Clock: rtc}
 	
 	println("DS1302 RTC Example Started!")
-	println("Initial time set to:", initialTime.Format("2006-01-02 15:04:05"))
 
 	for {
 		// Читаем время из RTC
@@ -38,7 +40,13 @@ func main() {
 		time.Sleep(100 * time.Millisecond)
 		led.Low()
 		
-		// Ждем секунду
-		time.Sleep(900 * time.Millisecond)
+		// Ждем секунду, обрабатывая запросы ds1302sync
+		for i := 0; i < 9; i++ {
+			for machine.Serial.Buffered() > 0 {
+				b, _ := machine.Serial.ReadByte()
+				sync.Feed(b, machine.Serial)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
 	}
 }
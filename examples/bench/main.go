@@ -0,0 +1,57 @@
+//go:build tinygo
+
+// Прошивка для замера скорости драйвера на железе.
+//
+// Подключение как в основном примере для ESP32: CLK -> GPIO18,
+// DAT -> GPIO19, RST -> GPIO5. Прошивка раз в несколько секунд выполняет
+// серии операций чтения и печатает среднее время одной операции
+// в микросекундах; цель для ReadTime на ESP32 с частотой 160 МГц - меньше
+// 200 мкс. Запись не замеряется, чтобы не сбивать время и ОЗУ модуля.
+//
+//   tinygo flash -target=esp32-coreboard-v2 -monitor ./examples/bench
+//
+// Накладные расходы драйвера без пауз на шине измеряют бенчмарки на хосте:
+//
+//   go test -bench . github.com/golangworker/ds1302-driver
+package main
+
+import (
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+)
+
+// readTimeTarget - цель для одного ReadTime.
+const readTimeTarget = 200 * time.Microsecond
+
+func main() {
+	rtc := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5)
+	rtc.Init()
+
+	var buf [ds1302.RAMSize]byte
+	for {
+		perOp := measure(1000, func() { rtc.ReadTime() })
+		verdict := "OK"
+		if perOp > readTimeTarget {
+			verdict = "SLOW"
+		}
+		println("ReadTime:     ", perOp.Microseconds(), "us/op", verdict)
+
+		println("ReadRAM:      ", measure(1000, func() { rtc.ReadRAM(0) }).Microseconds(), "us/op")
+		println("ReadRAMBytes: ", measure(100, func() { rtc.ReadRAMBytes(0, buf[:]) }).Microseconds(), "us/op")
+		println("Status:       ", measure(100, func() { rtc.Status() }).Microseconds(), "us/op")
+		println()
+
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// measure выполняет op n раз и возвращает среднее время одного вызова.
+func measure(n int, op func()) time.Duration {
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		op()
+	}
+	return time.Since(start) / time.Duration(n)
+}
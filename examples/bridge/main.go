@@ -0,0 +1,33 @@
+//go:build tinygo
+
+// Прошивка моста шины: выполняет команды линий DS1302, приходящие
+// по USB-serial, чтобы тесты на хосте работали с настоящей микросхемой:
+//
+//	stty -F /dev/ttyUSB0 raw -echo
+//	DS1302_BRIDGE=/dev/ttyUSB0 go test ./bridge
+package main
+
+import (
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+	"github.com/golangworker/ds1302-driver/bridge"
+)
+
+func main() {
+	// CLK -> GPIO18, DAT -> GPIO19, RST -> GPIO5
+	h := bridge.Handler{
+		CLK: ds1302.PinOf(machine.GPIO18),
+		DAT: ds1302.PinOf(machine.GPIO19),
+		RST: ds1302.PinOf(machine.GPIO5),
+	}
+
+	for {
+		for machine.Serial.Buffered() > 0 {
+			b, _ := machine.Serial.ReadByte()
+			h.Feed(b, machine.Serial)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
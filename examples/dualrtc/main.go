@@ -0,0 +1,77 @@
+//go:build tinygo
+
+// Пример резервирования часов двумя модулями DS1302 на ESP32.
+//
+// Модули делят линии CLK и DAT, а RST у каждого свой: невыбранная
+// микросхема держит DAT в высокоимпедансном состоянии и не мешает обмену.
+// Время берется с основного модуля, пока он исправен; раз в минуту оба
+// модуля сверяются, и об отказе или расхождении сообщается в UART.
+// Так работают автономные регистраторы, которые некому перезапустить.
+//
+// Подключение:
+//   - оба DS1302: CLK -> GPIO18, DAT -> GPIO19
+//   - основной DS1302: RST -> GPIO5
+//   - резервный DS1302: RST -> GPIO4
+package main
+
+import (
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+)
+
+// checkInterval - период сверки модулей.
+const checkInterval = time.Minute
+
+func main() {
+	primary := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5)
+	secondary := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO4)
+	primary.Init()
+	secondary.Init()
+	clocks := ds1302.NewRedundant(primary, secondary)
+
+	// Новый модуль, поставленный взамен отказавшего, получает время исправного.
+	st := clocks.Check()
+	report(st)
+	if t, err := clocks.ReadTime(); err == nil {
+		if st.Primary != ds1302.ReasonOK {
+			primary.SetTime(t)
+		}
+		if st.Secondary != ds1302.ReasonOK {
+			secondary.SetTime(t)
+		}
+	}
+
+	lastCheck := time.Now()
+	for {
+		t, err := clocks.ReadTime()
+		if err != nil {
+			println("ALERT:", err.Error())
+		} else {
+			println(t.Format(time.RFC3339))
+		}
+
+		if time.Since(lastCheck) >= checkInterval {
+			report(clocks.Check())
+			lastCheck = time.Now()
+		}
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// report сообщает в UART о неисправности или расхождении модулей.
+func report(st ds1302.RedundantStatus) {
+	if st.OK() {
+		return
+	}
+	if st.Primary != ds1302.ReasonOK {
+		println("ALERT: primary RTC failed:", st.Primary.String())
+	}
+	if st.Secondary != ds1302.ReasonOK {
+		println("ALERT: secondary RTC failed:", st.Secondary.String())
+	}
+	if st.Drifting {
+		println("ALERT: RTC modules disagree by", st.Skew.String())
+	}
+}
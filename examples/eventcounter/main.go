@@ -0,0 +1,104 @@
+//go:build tinygo
+
+// Пример счетчика событий в резервном ОЗУ DS1302 на ESP32: дверной
+// геркон или опрокидывающийся ковш дождемера.
+//
+// Прерывание от геркона только отмечает срабатывание; главный цикл
+// подавляет дребезг контактов, увеличивает счетчик в ОЗУ DS1302 и
+// запоминает время последнего события. Счетчик и время переживают
+// перезагрузку и отключение питания ESP32, пока у DS1302 есть батарея;
+// потеря питания самого модуля обнаруживается по заголовку раскладки ОЗУ.
+//
+// Подключение:
+//   - DS1302: CLK -> GPIO18, DAT -> GPIO19, RST -> GPIO5
+//   - Геркон: GPIO4 -> геркон -> GND (подтяжка к питанию внутри ESP32)
+package main
+
+import (
+	"encoding/binary"
+	"machine"
+	"sync/atomic"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+)
+
+// Раскладка ОЗУ: заголовок версии, затем запись счетчика.
+const (
+	layoutVersion = 1
+	recordAddr    = ds1302.LayoutHeaderSize
+	recordSize    = 8 // Число событий и время последнего (секунды Unix), uint32 LE
+)
+
+// debounce - время, за которое затихает дребезг контактов геркона.
+const debounce = 50 * time.Millisecond
+
+// edges - число срабатываний, отмеченных прерыванием и еще не учтенных.
+var edges atomic.Uint32
+
+func main() {
+	rtc := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5)
+	rtc.Init()
+
+	found, err := rtc.ApplyLayout(ds1302.Layout{Addr: 0, Version: layoutVersion})
+	switch {
+	case err != nil:
+		println("RAM layout:", err.Error())
+	case found == 0:
+		println("DS1302 RAM was lost (new module or dead battery), counter reset")
+	}
+
+	count, last := load(rtc)
+	println("events:", count, "last:", format(last))
+
+	reed := machine.GPIO4
+	reed.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	reed.SetInterrupt(machine.PinFalling, func(machine.Pin) {
+		// В прерывании нельзя работать с шиной DS1302: только отметка.
+		edges.Add(1)
+	})
+
+	for {
+		if edges.Load() == 0 {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		// Все фронты за время дребезга - одно событие.
+		time.Sleep(debounce)
+		edges.Store(0)
+
+		count++
+		last = uint32(rtc.ReadTime().Unix())
+		if err := store(rtc, count, last); err != nil {
+			println("store:", err.Error())
+		}
+		println("event", count, "at", format(last))
+	}
+}
+
+// load читает запись счетчика из ОЗУ DS1302.
+func load(rtc *ds1302.DS1302) (count, last uint32) {
+	var buf [recordSize]byte
+	if err := rtc.ReadRAMBytes(recordAddr, buf[:]); err != nil {
+		println("load:", err.Error())
+		return 0, 0
+	}
+	return binary.LittleEndian.Uint32(buf[0:4]), binary.LittleEndian.Uint32(buf[4:8])
+}
+
+// store записывает счетчик и время одним вызовом, снимая защиту
+// от записи один раз.
+func store(rtc *ds1302.DS1302, count, last uint32) error {
+	var buf [recordSize]byte
+	binary.LittleEndian.PutUint32(buf[0:4], count)
+	binary.LittleEndian.PutUint32(buf[4:8], last)
+	return rtc.WriteRAMBytes(recordAddr, buf[:])
+}
+
+// format возвращает время события или "never".
+func format(secs uint32) string {
+	if secs == 0 {
+		return "never"
+	}
+	return time.Unix(int64(secs), 0).UTC().Format(time.RFC3339)
+}
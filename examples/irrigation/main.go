@@ -0,0 +1,139 @@
+//go:build tinygo
+
+// Пример контроллера полива на ESP32 с DS1302.
+//
+// Недельная программа целиком хранится в резервном ОЗУ DS1302: моменты
+// включения - постоянные будильники планировщика, длительности поливов -
+// хранилище KV под теми же номерами. После перезагрузки или пропадания
+// питания контроллер восстанавливает программу из ОЗУ и, если питание
+// вернулось посреди полива, снова открывает клапан до конца окна полива.
+//
+// Подключение:
+//   - DS1302: CLK -> GPIO18, DAT -> GPIO19, RST -> GPIO5
+//   - Реле клапана -> GPIO4 (высокий уровень - клапан открыт)
+package main
+
+import (
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+)
+
+// Координаты участка - для полива от захода Солнца.
+const (
+	latitude  = 55.75
+	longitude = 37.62
+)
+
+// Раскладка ОЗУ: три будильника с днями недели (по 2 байта расширения)
+// и KV с тремя однобайтовыми длительностями - ровно 31 байт.
+const (
+	programs   = 3
+	alarmsAddr = 0
+)
+
+var (
+	alarmsSize = ds1302.AlarmsSize(programs) + programs*2
+	kvAddr     = alarmsAddr + alarmsSize
+	kvSize     = ds1302.RAMSize - kvAddr
+)
+
+// program - полив по умолчанию, записываемый в новый модуль.
+type program struct {
+	id      uint8
+	trigger ds1302.Trigger
+	minutes uint8
+}
+
+var defaults = []program{
+	// Понедельник, среда, пятница в 06:00 (UTC) - 20 минут.
+	{1, ds1302.Daily(6, 0).On(ds1302.Days(time.Monday, time.Wednesday, time.Friday)), 20},
+	// Выходные в 07:30 - 15 минут.
+	{2, ds1302.Daily(7, 30).On(ds1302.Weekend), 15},
+	// Вторник и четверг через полчаса после захода Солнца - 10 минут.
+	{3, ds1302.At(ds1302.Sunset, 30*time.Minute).On(ds1302.Days(time.Tuesday, time.Thursday)), 10},
+}
+
+var (
+	valve   = machine.GPIO4
+	closeAt time.Time // Конец текущего полива; нулевое - клапан закрыт
+)
+
+func main() {
+	valve.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	valve.Low()
+
+	rtc := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5)
+	rtc.Init()
+	durations := ds1302.NewKV(rtc, kvAddr, kvSize)
+
+	sched := ds1302.NewScheduler(rtc, latitude, longitude)
+	sched.PersistAlarms(alarmsAddr, alarmsSize)
+	sched.OnAlarm = func(id uint8, now time.Time) {
+		open(now.Add(duration(durations, id)))
+		sched.Acknowledge(id)
+	}
+
+	if err := sched.LoadAlarms(); err != nil {
+		// Новый модуль или батарея села: записываем программу по умолчанию.
+		println("program:", err.Error(), "- installing defaults")
+		install(sched, durations)
+	}
+	resume(sched, durations, rtc.ReadTime())
+
+	for {
+		if err := sched.Poll(); err != nil {
+			println("poll:", err.Error())
+		}
+		if !closeAt.IsZero() && !rtc.ReadTime().Before(closeAt) {
+			println("valve closed")
+			valve.Low()
+			closeAt = time.Time{}
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// install записывает программу по умолчанию в ОЗУ.
+func install(sched *ds1302.Scheduler, durations *ds1302.KV) {
+	durations.Format()
+	for _, p := range defaults {
+		if _, err := sched.AddAlarm(p.id, p.trigger); err != nil {
+			println("alarm", p.id, ":", err.Error())
+		}
+		if err := durations.Set(p.id, []byte{p.minutes}); err != nil {
+			println("duration", p.id, ":", err.Error())
+		}
+	}
+}
+
+// resume открывает клапан, если now попадает в окно полива одной из
+// программ, сохраненных в ОЗУ. Так перезагрузка посреди полива
+// не обрывает его.
+func resume(sched *ds1302.Scheduler, durations *ds1302.KV, now time.Time) {
+	for _, job := range sched.Alarms() {
+		d := duration(durations, job.ID)
+		if start, ok := sched.LastWithin(job.Trigger, now, d); ok {
+			open(start.Add(d))
+		}
+	}
+}
+
+// duration возвращает длительность полива программы id из ОЗУ.
+func duration(durations *ds1302.KV, id uint8) time.Duration {
+	var buf [1]byte
+	if _, err := durations.Get(id, buf[:]); err != nil {
+		return 0
+	}
+	return time.Duration(buf[0]) * time.Minute
+}
+
+// open открывает клапан до until; пересекающиеся поливы продлевают окно.
+func open(until time.Time) {
+	if until.After(closeAt) {
+		closeAt = until
+	}
+	println("valve open until", closeAt.Format("15:04"))
+	valve.High()
+}
@@ -0,0 +1,76 @@
+//go:build tinygo
+
+// Пример батарейного регистратора данных на ESP32 с DS1302.
+//
+// Каждый цикл устройство просыпается, увеличивает счетчик запусков
+// в резервном ОЗУ DS1302, читает датчик, ставит на измерение метку
+// времени из RTC и засыпает до следующего измерения. Счетчик и часы
+// продолжают работать от батареи DS1302, пока ESP32 спит или обесточен.
+//
+// Подключение:
+//   - DS1302: CLK -> GPIO18, DAT -> GPIO19, RST -> GPIO5
+//   - Датчик с цифровым выходом (дождь, влажность почвы, геркон) -> GPIO4
+package main
+
+import (
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+)
+
+// interval - период измерений.
+const interval = 5 * time.Minute
+
+// bootCounterAddr - адрес счетчика запусков в резервном ОЗУ.
+const bootCounterAddr = 0
+
+func main() {
+	deepSleep(wake())
+}
+
+// wake выполняет один цикл после пробуждения: увеличивает счетчик
+// запусков, делает измерение и возвращает время до следующего.
+// Как после настоящего глубокого сна, периферия настраивается заново.
+func wake() time.Duration {
+	sensor := machine.GPIO4
+	sensor.Configure(machine.PinConfig{Mode: machine.PinInput})
+
+	rtc := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5)
+	rtc.Init()
+	boots := ds1302.NewBootCounter(rtc, bootCounterAddr)
+
+	n, err := boots.Increment()
+	if err != nil {
+		println("boot counter:", err.Error())
+	}
+
+	t := rtc.ReadTime()
+	value := 0
+	if sensor.Get() {
+		value = 1
+	}
+	println(t.Format(time.RFC3339), "boot", n, "sensor", value)
+
+	return untilNext(t)
+}
+
+// untilNext возвращает время до следующего измерения, выровненного
+// по сетке interval часов реального времени, а не по генератору ESP32.
+func untilNext(t time.Time) time.Duration {
+	return t.Truncate(interval).Add(interval).Sub(t)
+}
+
+// deepSleep переводит устройство в сон на d и не возвращается.
+//
+// TinyGo пока не предоставляет API глубокого сна для ESP32, поэтому
+// здесь сон заменен задержкой и повторным вызовом wake, как при
+// пробуждении. Когда глубокий сон доступен, достаточно заменить тело
+// функции вызовом сна: после пробуждения ESP32 заново выполняет main,
+// а все необходимое состояние уже хранится в DS1302.
+func deepSleep(d time.Duration) {
+	for {
+		time.Sleep(d)
+		d = wake()
+	}
+}
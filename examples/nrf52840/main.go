@@ -0,0 +1,50 @@
+//go:build tinygo && nrf52840
+
+// Пример работы с DS1302 на nRF52840 (Arduino Nano 33 BLE).
+//
+// Подключение:
+//   - VCC -> 3.3V (у Nano 33 BLE нет 5 В на выводах, DS1302 работает от 2 В)
+//   - GND -> GND
+//   - CLK -> D2
+//   - DAT -> D3
+//   - RST -> D4
+//
+// На других платах с nRF52840 (Feather, nice!nano, XIAO BLE) подойдут
+// любые свободные GPIO - достаточно поменять пины ниже. Паузы между фронтами
+// драйвер на nRF выдерживает активным ожиданием, настраивать их не нужно.
+//
+// Вывод идет в USB-serial. Сборка и прошивка:
+//
+//   tinygo flash -target=nano-33-ble -monitor ./examples/nrf52840
+package main
+
+import (
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+)
+
+func main() {
+	rtc := ds1302.NewDS1302(machine.D2, machine.D3, machine.D4)
+	rtc.Init()
+
+	// Дать хосту время открыть USB-serial, чтобы не потерять первые строки.
+	time.Sleep(2 * time.Second)
+	machine.Serial.Write([]byte("DS1302 RTC nRF52840 example started\r\n"))
+
+	if ok, reason := rtc.IsTimeValid(); !ok {
+		rtc.SetTime(time.Date(2024, 8, 5, 21, 0, 0, 0, time.UTC))
+		machine.Serial.Write([]byte("RTC time was not valid (" + reason.String() + "), initialized to default\r\n"))
+	}
+
+	var buf [32]byte
+	for {
+		if dt, err := rtc.ReadDateTime(); err == nil {
+			line := dt.AppendRFC3339(buf[:0])
+			line = append(line, '\r', '\n')
+			machine.Serial.Write(line)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/golangworker/ds1302-driver/examples/ntp
+
+go 1.22.1
+
+require (
+	github.com/golangworker/ds1302-driver v0.0.0
+	tinygo.org/x/drivers v0.36.0
+)
+
+require github.com/google/shlex v0.0.0-20191202100458-e7afc7fbc510 // indirect
+
+replace github.com/golangworker/ds1302-driver => ../../
@@ -0,0 +1,97 @@
+//go:build tinygo
+
+// Пример синхронизации DS1302 по NTP через WiFi.
+//
+// Раз в сутки устройство подключается к WiFi, запрашивает время
+// у NTP сервера (sntp.Source) и подстраивает DS1302 (ds1302.Syncer).
+// В остальное время часы читаются локально из RTC, без сети.
+//
+// TinyGo работает с WiFi через netdev. Радиомодуль ESP32 используется
+// как сетевой сопроцессор с прошивкой NINA (u-blox NINA-W102 на платах
+// Arduino Nano RP2040 Connect, Nano 33 IoT, MKR WiFi 1010) или ESP-AT.
+// Встроенный WiFi ESP32 самим TinyGo пока не поддерживается.
+//
+// Подключение DS1302: CLK -> D2, DAT -> D3, RST -> D4.
+//
+// Пример вынесен в отдельный модуль, чтобы драйвер не зависел от
+// tinygo.org/x/drivers. Сборка и прошивка из этого каталога:
+//
+//   tinygo flash -target=nano-rp2040 -ldflags="-X main.ssid=MyWiFi -X main.pass=secret" .
+package main
+
+import (
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+	"github.com/golangworker/ds1302-driver/sntp"
+	"tinygo.org/x/drivers/netlink"
+	"tinygo.org/x/drivers/netlink/probe"
+)
+
+// Параметры WiFi задаются при сборке через -ldflags.
+var (
+	ssid string
+	pass string
+)
+
+const ntpServer = "pool.ntp.org:123"
+
+// Повторы после неудачной синхронизации: пауза удваивается от
+// minRetry до maxRetry, чтобы не подключаться к WiFi каждую секунду.
+const (
+	minRetry = time.Minute
+	maxRetry = time.Hour
+)
+
+func main() {
+	rtc := ds1302.NewDS1302(machine.D2, machine.D3, machine.D4)
+	rtc.Init()
+
+	link, _ := probe.Probe()
+
+	syncer := ds1302.Syncer{
+		RTC:      rtc,
+		Source:   sntp.Source{Server: ntpServer},
+		Interval: 24 * time.Hour,
+	}
+
+	var retryAt time.Time
+	backoff := minRetry
+	for {
+		if syncer.Due() && !time.Now().Before(retryAt) {
+			if syncOnce(link, &syncer) {
+				backoff = minRetry
+			} else {
+				println("next attempt in", backoff.String())
+				retryAt = time.Now().Add(backoff)
+				backoff = min(2*backoff, maxRetry)
+			}
+		}
+
+		println("RTC time:", rtc.ReadTime().Format("2006-01-02 15:04:05"))
+		time.Sleep(time.Second)
+	}
+}
+
+// syncOnce подключается к WiFi, синхронизирует часы и отключается.
+// Возвращает false, если подключиться или синхронизироваться не удалось.
+func syncOnce(link netlink.Netlinker, syncer *ds1302.Syncer) bool {
+	err := link.NetConnect(&netlink.ConnectParams{
+		Ssid:       ssid,
+		Passphrase: pass,
+	})
+	if err != nil {
+		println("WiFi connect failed:", err.Error())
+		return false
+	}
+	defer link.NetDisconnect()
+
+	offset, err := syncer.Sync()
+	if err != nil {
+		println("NTP sync failed:", err.Error())
+		return false
+	}
+	println("NTP sync done, RTC offset was", offset.String())
+	return true
+}
@@ -0,0 +1,63 @@
+//go:build tinygo && rp2040
+
+// Пример работы с DS1302 на Raspberry Pi Pico (RP2040).
+//
+// Подключение:
+//   - VCC -> 3V3(OUT) (pin 36)
+//   - GND -> GND (pin 38)
+//   - CLK -> GP2 (pin 4)
+//   - DAT -> GP3 (pin 5)
+//   - RST -> GP4 (pin 6)
+//
+// Вывод идет в UART0 (TX -> GP0, RX -> GP1, 115200 бод), например через
+// USB-UART адаптер или Picoprobe. Сборка и прошивка:
+//
+//   tinygo flash -target=pico ./examples/pico
+package main
+
+import (
+	"machine"
+	"strconv"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+)
+
+func main() {
+	uart := machine.UART0
+	uart.Configure(machine.UARTConfig{
+		BaudRate: 115200,
+		TX:       machine.UART0_TX_PIN,
+		RX:       machine.UART0_RX_PIN,
+	})
+
+	led := machine.LED
+	led.Configure(machine.PinConfig{Mode: machine.PinOutput})
+
+	// Любые GPIO Pico подходят для DS1302; GP2-GP4 выбраны рядом
+	// с GND, чтобы модуль подключался одним шлейфом.
+	rtc := ds1302.NewDS1302(machine.GP2, machine.GP3, machine.GP4)
+	rtc.Init()
+
+	uart.Write([]byte("DS1302 RTC Pico example started\r\n"))
+
+	// Новые модули поставляются с остановленным генератором и нулевой датой.
+	// Устанавливаем время при первом запуске, если год выглядит сброшенным.
+	if rtc.ReadTime().Year() <= 2000 {
+		rtc.SetTime(time.Date(2024, 8, 5, 21, 0, 0, 0, time.UTC))
+		uart.Write([]byte("RTC time was not set, initialized to default\r\n"))
+	}
+
+	for {
+		t := rtc.ReadTime()
+
+		uart.Write([]byte("RTC time: "))
+		uart.Write([]byte(t.Format("2006-01-02 15:04:05")))
+		uart.Write([]byte(" (unix " + strconv.FormatInt(t.Unix(), 10) + ")\r\n"))
+
+		led.High()
+		time.Sleep(100 * time.Millisecond)
+		led.Low()
+		time.Sleep(900 * time.Millisecond)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build tinygo
+
+// Пример часов на OLED-дисплее SSD1306 128x64 (I2C) с DS1302 на ESP32.
+//
+// Подключение:
+//   - DS1302: CLK -> GPIO18, DAT -> GPIO19, RST -> GPIO5
+//   - SSD1306: SDA -> GPIO21, SCL -> GPIO22, адрес 0x3C
+//
+// На экран выводятся время ЧЧ:ММ:СС, дата и полоса, показывающая
+// долю текущей секунды (через ds1302.Interpolator).
+//
+// Пример вынесен в отдельный модуль, чтобы драйвер не зависел от
+// tinygo.org/x/drivers. Сборка и прошивка из этого каталога:
+//
+//   tinygo flash -target=esp32-coreboard-v2 .
+package main
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"github.com/golangworker/ds1302-driver"
+	"tinygo.org/x/drivers/ssd1306"
+	"tinygo.org/x/tinyfont"
+	"tinygo.org/x/tinyfont/freemono"
+)
+
+const (
+	width  = 128
+	height = 64
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{
+		Frequency: 400 * machine.KHz,
+		SDA:       machine.GPIO21,
+		SCL:       machine.GPIO22,
+	})
+	display := ssd1306.NewI2C(machine.I2C0)
+	display.Configure(ssd1306.Config{Width: width, Height: height, Address: 0x3C})
+	display.ClearDisplay()
+
+	rtc := ds1302.NewDS1302(machine.GPIO18, machine.GPIO19, machine.GPIO5)
+	rtc.Init()
+	clock := ds1302.NewInterpolator(rtc)
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	var buf [10]byte
+
+	for {
+		dt, frac, synced := clock.Read()
+
+		display.ClearBuffer()
+
+		tinyfont.WriteLine(display, &freemono.Bold12pt7b, 6, 26, string(dt.AppendClock(buf[:0])), white)
+		tinyfont.WriteLine(display, &freemono.Regular9pt7b, 10, 50, string(dt.AppendDate(buf[:0])), white)
+
+		// Полоса доли секунды внизу экрана
+		if synced {
+			w := int16(frac * width / time.Second)
+			for x := int16(0); x < w; x++ {
+				display.SetPixel(x, height-2, white)
+				display.SetPixel(x, height-1, white)
+			}
+		}
+
+		display.Display()
+		time.Sleep(40 * time.Millisecond)
+	}
+}
@@ -0,0 +1,31 @@
+package ds1302
+
+import (
+    "errors"
+    "time"
+)
+
+// ErrInvalidFields возвращается SetTimeFields для полей вне допустимых
+// диапазонов DS1302 (год 2000-2099, существующая дата, время 00:00:00-23:59:59).
+var ErrInvalidFields = errors.New("ds1302: invalid date/time fields")
+
+// SetTimeFields устанавливает время по отдельным полям - для кода, который
+// не работает с time.Time (последовательная консоль, BCD клавиатуры).
+// Поля проверяются до записи: time.Date молча нормализовал бы 31 апреля
+// в 1 мая. Запись, журнал и GuardBackwards - как у SetTime.
+func (d *DS1302) SetTimeFields(year int, month, day, hour, min, sec uint8) error {
+    d.mu.Lock()
+    defer d.unlock()
+    if year < 2000 || year > 2099 || month < 1 || month > 12 ||
+        day < 1 || day > daysInMonth(year, month) ||
+        hour > 23 || min > 59 || sec > 59 {
+        return d.fail(ErrInvalidFields)
+    }
+    t := time.Date(year, time.Month(month), int(day), int(hour), int(min), int(sec), 0, time.UTC)
+    return d.setTimeFrom(t, AdjustManual)
+}
+
+// daysInMonth возвращает число дней в месяце month года year.
+func daysInMonth(year int, month uint8) uint8 {
+    return uint8(time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day())
+}
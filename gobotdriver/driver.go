@@ -0,0 +1,155 @@
+// Package gobotdriver предоставляет драйвер gobot для микросхемы DS1302.
+//
+// Драйвер использует цифровые линии адаптора gobot (например, raspi)
+// и публикует событие MinuteEvent при каждой смене минуты на часах.
+//
+// Пример использования:
+//
+//     r := raspi.NewAdaptor()
+//     rtc := gobotdriver.NewDriver(r, "11", "13", "15")
+//     rtc.On(gobotdriver.MinuteEvent, func(data interface{}) {
+//         fmt.Println("minute:", data.(time.Time))
+//     })
+//     robot := gobot.NewRobot("clock", []gobot.Connection{r}, []gobot.Device{rtc})
+//     robot.Start()
+//
+package gobotdriver
+
+import (
+    "sync"
+    "time"
+
+    "github.com/golangworker/ds1302-driver"
+    "gobot.io/x/gobot/v2"
+)
+
+// События драйвера.
+const (
+    MinuteEvent = "minute" // Смена минуты, данные - time.Time
+    ErrorEvent  = "error"  // Ошибка адаптора, данные - error
+)
+
+// DefaultPollInterval - период опроса часов по умолчанию.
+const DefaultPollInterval = time.Second
+
+// Driver - драйвер gobot для DS1302.
+type Driver struct {
+    name       string
+    connection DigitalReadWriter
+    errs       *errorSink
+
+    mu  sync.Mutex
+    rtc *ds1302.DS1302
+
+    // PollInterval - период опроса часов для обнаружения смены минуты.
+    PollInterval time.Duration
+
+    halt chan struct{}
+    done chan struct{}
+    gobot.Eventer
+}
+
+// NewDriver создает драйвер DS1302 на линиях clk, dat и rst адаптора a.
+func NewDriver(a DigitalReadWriter, clk, dat, rst string) *Driver {
+    errs := &errorSink{}
+    d := &Driver{
+        name:         gobot.DefaultName("DS1302"),
+        connection:   a,
+        errs:         errs,
+        PollInterval: DefaultPollInterval,
+        Eventer:      gobot.NewEventer(),
+        rtc: ds1302.New(
+            pin{conn: a, name: clk, errs: errs},
+            pin{conn: a, name: dat, errs: errs},
+            pin{conn: a, name: rst, errs: errs},
+        ),
+    }
+    d.AddEvent(MinuteEvent)
+    d.AddEvent(ErrorEvent)
+    return d
+}
+
+// Name возвращает имя драйвера.
+func (d *Driver) Name() string { return d.name }
+
+// SetName задает имя драйвера.
+func (d *Driver) SetName(n string) { d.name = n }
+
+// Connection возвращает адаптор, к которому подключен драйвер.
+func (d *Driver) Connection() gobot.Connection {
+    if conn, ok := d.connection.(gobot.Connection); ok {
+        return conn
+    }
+    return nil
+}
+
+// Start инициализирует линии и запускает опрос часов.
+func (d *Driver) Start() error {
+    d.mu.Lock()
+    d.rtc.Init()
+    last := d.rtc.ReadTime()
+    d.mu.Unlock()
+    if err := d.errs.take(); err != nil {
+        return err
+    }
+
+    d.halt = make(chan struct{})
+    d.done = make(chan struct{})
+    go d.poll(last)
+    return nil
+}
+
+// Halt останавливает опрос часов.
+func (d *Driver) Halt() error {
+    if d.halt == nil {
+        return nil
+    }
+    close(d.halt)
+    <-d.done
+    d.halt = nil
+    return nil
+}
+
+// SetTime устанавливает время в DS1302.
+func (d *Driver) SetTime(t time.Time) error {
+    d.mu.Lock()
+    err := d.rtc.SetTime(t)
+    d.mu.Unlock()
+    if pinErr := d.errs.take(); pinErr != nil {
+        return pinErr
+    }
+    return err
+}
+
+// ReadTime читает время из DS1302.
+func (d *Driver) ReadTime() (time.Time, error) {
+    d.mu.Lock()
+    t := d.rtc.ReadTime()
+    d.mu.Unlock()
+    return t, d.errs.take()
+}
+
+// poll опрашивает часы и публикует MinuteEvent при смене минуты.
+func (d *Driver) poll(last time.Time) {
+    defer close(d.done)
+
+    ticker := time.NewTicker(d.PollInterval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-d.halt:
+            return
+        case <-ticker.C:
+        }
+
+        t, err := d.ReadTime()
+        if err != nil {
+            d.Publish(ErrorEvent, err)
+            continue
+        }
+        if !t.Truncate(time.Minute).Equal(last.Truncate(time.Minute)) {
+            d.Publish(MinuteEvent, t)
+        }
+        last = t
+    }
+}
@@ -0,0 +1,16 @@
+module github.com/golangworker/ds1302-driver/gobotdriver
+
+go 1.24.0
+
+require (
+	github.com/golangworker/ds1302-driver v0.0.0
+	gobot.io/x/gobot/v2 v2.6.0
+)
+
+require (
+	github.com/gofrs/uuid v4.4.0+incompatible // indirect
+	github.com/hashicorp/errwrap v1.1.0 // indirect
+	github.com/hashicorp/go-multierror v1.1.1 // indirect
+)
+
+replace github.com/golangworker/ds1302-driver => ../
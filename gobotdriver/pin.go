@@ -0,0 +1,65 @@
+package gobotdriver
+
+import (
+    "sync"
+)
+
+// DigitalReadWriter - возможности адаптора gobot, необходимые драйверу.
+// Сигнатуры совпадают с gpio.DigitalReader и gpio.DigitalWriter,
+// поэтому подходят адапторы raspi, tinkerboard, jetson и другие.
+type DigitalReadWriter interface {
+    DigitalRead(pin string) (val int, err error)
+    DigitalWrite(pin string, val byte) error
+}
+
+// pin реализует ds1302.Pin поверх адаптора gobot.
+// Адапторы gobot сами переключают направление линии при чтении и записи,
+// поэтому ConfigureInput/ConfigureOutput ничего не делают.
+// Первая ошибка адаптора запоминается в общем errorSink.
+type pin struct {
+    conn DigitalReadWriter
+    name string
+    errs *errorSink
+}
+
+func (p pin) ConfigureOutput() {}
+func (p pin) ConfigureInput()  {}
+
+func (p pin) High() {
+    p.errs.record(p.conn.DigitalWrite(p.name, 1))
+}
+
+func (p pin) Low() {
+    p.errs.record(p.conn.DigitalWrite(p.name, 0))
+}
+
+func (p pin) Get() bool {
+    val, err := p.conn.DigitalRead(p.name)
+    p.errs.record(err)
+    return val != 0
+}
+
+// errorSink хранит первую ошибку адаптора до ее извлечения.
+type errorSink struct {
+    mu  sync.Mutex
+    err error
+}
+
+func (s *errorSink) record(err error) {
+    if err == nil {
+        return
+    }
+    s.mu.Lock()
+    if s.err == nil {
+        s.err = err
+    }
+    s.mu.Unlock()
+}
+
+func (s *errorSink) take() error {
+    s.mu.Lock()
+    err := s.err
+    s.err = nil
+    s.mu.Unlock()
+    return err
+}
@@ -0,0 +1,168 @@
+package ds1302_test
+
+import (
+    "os"
+    "testing"
+    "time"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+    "github.com/golangworker/ds1302-driver/bridge"
+    "github.com/golangworker/ds1302-driver/sim"
+)
+
+func date(year int, month time.Month, day, hour, min, sec int) time.Time {
+    return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+}
+
+// goldenCases - граничные даты, ожидаемые регистры в 24-часовом формате
+// (секунды, минуты, часы, дата, месяц, день недели с понедельником 1, год)
+// и время через секунду после установки.
+var goldenCases = []struct {
+    t    time.Time
+    regs [7]uint8
+    next time.Time
+}{
+    {date(2000, 1, 1, 0, 0, 0), [7]uint8{0x00, 0x00, 0x00, 0x01, 0x01, 0x06, 0x00}, date(2000, 1, 1, 0, 0, 1)},
+    {date(2000, 2, 28, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x28, 0x02, 0x01, 0x00}, date(2000, 2, 29, 0, 0, 0)},
+    {date(2000, 2, 29, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x29, 0x02, 0x02, 0x00}, date(2000, 3, 1, 0, 0, 0)},
+    {date(2001, 2, 28, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x28, 0x02, 0x03, 0x01}, date(2001, 3, 1, 0, 0, 0)},
+    {date(2024, 2, 29, 12, 0, 0), [7]uint8{0x00, 0x00, 0x12, 0x29, 0x02, 0x04, 0x24}, date(2024, 2, 29, 12, 0, 1)},
+    {date(2024, 2, 29, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x29, 0x02, 0x04, 0x24}, date(2024, 3, 1, 0, 0, 0)},
+    {date(2025, 1, 31, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x31, 0x01, 0x05, 0x25}, date(2025, 2, 1, 0, 0, 0)},
+    {date(2025, 4, 30, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x30, 0x04, 0x03, 0x25}, date(2025, 5, 1, 0, 0, 0)},
+    {date(2025, 6, 30, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x30, 0x06, 0x01, 0x25}, date(2025, 7, 1, 0, 0, 0)},
+    {date(2025, 9, 30, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x30, 0x09, 0x02, 0x25}, date(2025, 10, 1, 0, 0, 0)},
+    {date(2025, 11, 30, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x30, 0x11, 0x07, 0x25}, date(2025, 12, 1, 0, 0, 0)},
+    {date(2025, 12, 31, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x31, 0x12, 0x03, 0x25}, date(2026, 1, 1, 0, 0, 0)},
+    {date(2025, 7, 15, 0, 59, 59), [7]uint8{0x59, 0x59, 0x00, 0x15, 0x07, 0x02, 0x25}, date(2025, 7, 15, 1, 0, 0)},
+    {date(2025, 7, 15, 11, 59, 59), [7]uint8{0x59, 0x59, 0x11, 0x15, 0x07, 0x02, 0x25}, date(2025, 7, 15, 12, 0, 0)},
+    {date(2025, 7, 15, 12, 59, 59), [7]uint8{0x59, 0x59, 0x12, 0x15, 0x07, 0x02, 0x25}, date(2025, 7, 15, 13, 0, 0)},
+    {date(2099, 2, 28, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x28, 0x02, 0x06, 0x99}, date(2099, 3, 1, 0, 0, 0)},
+    {date(2099, 12, 31, 23, 59, 59), [7]uint8{0x59, 0x59, 0x23, 0x31, 0x12, 0x04, 0x99}, date(2000, 1, 1, 0, 0, 0)},
+}
+
+// simClock - управляемые часы модели. Если step не ноль, каждое обращение
+// модели к часам сдвигает их на step, имитируя медленную шину. Для
+// настоящей микросхемы (hardware) step не действует, а Advance просто ждет.
+type simClock struct {
+    now      time.Time
+    step     time.Duration
+    hardware bool
+}
+
+func (c *simClock) Now() time.Time {
+    t := c.now
+    c.now = c.now.Add(c.step)
+    return t
+}
+
+func (c *simClock) Advance(d time.Duration) {
+    if c.hardware {
+        time.Sleep(d)
+        return
+    }
+    c.now = c.now.Add(d)
+}
+
+// newTestRTC возвращает драйвер для тестов пакета. По умолчанию это модель
+// микросхемы с управляемым временем и без пауз между фронтами. Если задана
+// переменная DS1302_BRIDGE (порт платы с прошивкой examples/bridge, см.
+// bridge.TestHardware), тесты идут на настоящей микросхеме, а вместо модели
+// возвращается nil: тесты, которым нужны ее регистры, пропускаются.
+// Тесты на железе перезаписывают время и ОЗУ микросхемы.
+func newTestRTC(tb testing.TB, mode ds1302.HourMode) (*ds1302.DS1302, *sim.Chip, *simClock) {
+    tb.Helper()
+    var rtc *ds1302.DS1302
+    var chip *sim.Chip
+    clock := &simClock{now: date(2030, 1, 1, 0, 0, 0)}
+    if path := os.Getenv("DS1302_BRIDGE"); path != "" {
+        f, err := os.OpenFile(path, os.O_RDWR, 0)
+        if err != nil {
+            tb.Fatal(err)
+        }
+        tb.Cleanup(func() { f.Close() })
+        remote, err := bridge.Dial(f)
+        if err != nil {
+            tb.Fatalf("Dial(%s): %v", path, err)
+        }
+        rtc = ds1302.New(remote.Pins())
+        clock.hardware = true
+    } else {
+        chip = sim.New()
+        chip.Now = clock.Now
+        rtc = ds1302.New(chip.Pins())
+        rtc.Configure(ds1302.Config{DelayFn: func(uint32) {}})
+    }
+    if err := rtc.Init(); err != nil {
+        tb.Fatalf("Init: %v", err)
+    }
+    if err := rtc.SetHourMode(mode); err != nil {
+        tb.Fatalf("SetHourMode(%v): %v", mode, err)
+    }
+    return rtc, chip, clock
+}
+
+// needChip пропускает тест, если он идет на настоящей микросхеме.
+func needChip(tb testing.TB, chip *sim.Chip) {
+    tb.Helper()
+    if chip == nil {
+        tb.Skip("needs the sim model registers; DS1302_BRIDGE is set")
+    }
+}
+
+func weekdayOf(t time.Time) ds1302.Weekday {
+    return ds1302.Weekday((int(t.Weekday())+6)%7 + 1)
+}
+
+func TestGoldenRegisters(t *testing.T) {
+    for _, tc := range goldenCases {
+        rtc, chip, _ := newTestRTC(t, ds1302.Hour24)
+        needChip(t, chip)
+        if err := rtc.SetTime(tc.t); err != nil {
+            t.Fatalf("SetTime(%v): %v", tc.t, err)
+        }
+        regs := chip.Registers()
+        var got [7]uint8
+        copy(got[:], regs[:7])
+        if got != tc.regs {
+            t.Errorf("SetTime(%v): registers % x, want % x", tc.t, got, tc.regs)
+        }
+        if regs[7] != 0x80 {
+            t.Errorf("SetTime(%v): WP = %#02x, want 0x80", tc.t, regs[7])
+        }
+    }
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+    for _, mode := range []ds1302.HourMode{ds1302.Hour24, ds1302.Hour12} {
+        for _, tc := range goldenCases {
+            rtc, _, clock := newTestRTC(t, mode)
+            if err := rtc.SetTime(tc.t); err != nil {
+                t.Fatalf("mode %v: SetTime(%v): %v", mode, tc.t, err)
+            }
+            if got := rtc.ReadTime(); !got.Equal(tc.t) {
+                t.Errorf("mode %v: ReadTime after SetTime(%v) = %v", mode, tc.t, got)
+            }
+            if dt, err := rtc.ReadDateTime(); err != nil {
+                t.Errorf("mode %v: ReadDateTime after SetTime(%v): %v", mode, tc.t, err)
+            } else if got, want := dt.Weekday, weekdayOf(tc.t); got != want {
+                t.Errorf("mode %v: weekday of %v = %v, want %v", mode, tc.t, got, want)
+            }
+            if got := rtc.GetHourMode(); got != mode {
+                t.Errorf("mode %v: SetTime(%v) changed hour mode to %v", mode, tc.t, got)
+            }
+
+            clock.Advance(time.Second)
+            if got := rtc.ReadTime(); !got.Equal(tc.next) {
+                t.Errorf("mode %v: one second after %v: ReadTime = %v, want %v", mode, tc.t, got, tc.next)
+            }
+            // После 2099 микросхема переходит к 2000 году, но день недели
+            // просто продолжает счет.
+            if dt, err := rtc.ReadDateTime(); err != nil {
+                t.Errorf("mode %v: ReadDateTime one second after %v: %v", mode, tc.t, err)
+            } else if got, want := dt.Weekday, weekdayOf(tc.next); got != want && !tc.next.Before(tc.t) {
+                t.Errorf("mode %v: weekday one second after %v = %v, want %v", mode, tc.t, got, want)
+            }
+        }
+    }
+}
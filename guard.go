@@ -0,0 +1,54 @@
+package ds1302
+
+import (
+    "errors"
+    "time"
+)
+
+// ErrTimeBackwards возвращается, если при включенной GuardBackwards
+// новое время раньше текущего времени часов.
+var ErrTimeBackwards = errors.New("ds1302: refusing to set time backwards")
+
+// ErrTimeRange возвращается при установке времени вне 2000-2099 годов
+// по UTC - диапазона регистра года DS1302.
+var ErrTimeRange = errors.New("ds1302: time outside years 2000-2099")
+
+// GuardBackwards включает или выключает запрет перевода часов назад.
+// При включенном запрете SetTime, SetTimeAligned и Tx.SetTime отклоняют
+// время раньше текущего с ErrTimeBackwards, чтобы ошибочный ответ NTP
+// или опечатка оператора не нарушили порядок записей в журнале.
+// Намеренный перевод назад выполняется через ForceSetTime
+// (Tx.ForceSetTime, Syncer.Force).
+func (d *DS1302) GuardBackwards(enable bool) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    d.guardBackwards = enable
+}
+
+// checkBackwards проверяет t относительно текущего времени часов.
+// Если регистры часов содержат недопустимые значения, установка
+// разрешается: это единственный способ восстановить часы.
+func (d *DS1302) checkBackwards(t time.Time) error {
+    if !d.guardBackwards {
+        return nil
+    }
+    now, err := d.readTime()
+    if err != nil {
+        return nil
+    }
+    if t.Truncate(time.Second).Before(now) {
+        return d.fail(ErrTimeBackwards)
+    }
+    return nil
+}
+
+// clockTime приводит t к UTC, в котором идут часы, и проверяет, что год
+// помещается в регистр DS1302. Иначе время со смещением (+03:00)
+// записалось бы как местное, а 2150 год - как 2050.
+func (d *DS1302) clockTime(t time.Time) (time.Time, error) {
+    t = t.UTC()
+    if t.Year() < 2000 || t.Year() > 2099 {
+        return t, d.fail(ErrTimeRange)
+    }
+    return t, nil
+}
@@ -0,0 +1,85 @@
+package ds1302
+
+import (
+    "errors"
+
+    "github.com/golangworker/ds1302-driver/bcd"
+    "github.com/golangworker/ds1302-driver/codec"
+)
+
+// ErrInvalidHourMode возвращается при неизвестном формате часов.
+var ErrInvalidHourMode = errors.New("ds1302: invalid hour mode")
+
+// GetHourMode возвращает формат, в котором микросхема сейчас хранит часы
+// (Hour24, если устройство закрыто).
+func (d *DS1302) GetHourMode() HourMode {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if d.checkOpen() != nil {
+        return Hour24
+    }
+    return codec.HourModeOf(d.readRegister(DS1302_HOURS_READ))
+}
+
+// SetHourMode переключает микросхему между 12- и 24-часовым форматом,
+// пересчитывая текущее значение часов. Нужен, когда с тем же модулем
+// работает другая прошивка (например, на Arduino), ожидающая определенный формат.
+//
+// Если до смены часа осталась последняя секунда, переключение выполняется
+// после смены часа, чтобы не записать устаревшее значение; на время
+// ожидания блокировка устройства снимается.
+func (d *DS1302) SetHourMode(mode HourMode) error {
+    if mode != Hour12 && mode != Hour24 {
+        d.mu.Lock()
+        defer d.mu.Unlock()
+        return d.fail(ErrInvalidHourMode)
+    }
+    for {
+        done, err := d.trySetHourMode(mode)
+        if done || err != nil {
+            return err
+        }
+        if _, err := d.WaitForSecondEdge(); err != nil {
+            return err
+        }
+    }
+}
+
+// trySetHourMode пересчитывает регистр часов под блокировкой. done ложно,
+// если идет последняя секунда часа и переключение нужно повторить после нее.
+func (d *DS1302) trySetHourMode(mode HourMode) (done bool, err error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return false, err
+    }
+    
+    if d.readRegister(DS1302_MINUTES_READ) == 0x59 &&
+        d.readRegister(DS1302_SECONDS_READ) == 0x59 {
+        return false, nil
+    }
+    
+    reg := d.readRegister(DS1302_HOURS_READ)
+    if codec.HourModeOf(reg) == mode {
+        return true, nil
+    }
+    if !validHours(reg) {
+        return false, d.fail(ErrInvalidBCD)
+    }
+    
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    d.writeRegister(DS1302_HOURS_WRITE, codec.EncodeHours(codec.DecodeHours(reg), mode))
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    return true, nil
+}
+
+// validHours проверяет регистр часов в его формате: 1-12 с AM/PM
+// или 0-23 (в 24-часовом формате бит 5 - старшая цифра часа).
+func validHours(reg uint8) bool {
+    if codec.HourModeOf(reg) == Hour12 {
+        h := reg & 0x1F
+        return bcd.Valid(h) && h >= 0x01 && h <= 0x12
+    }
+    h := reg & 0x3F
+    return bcd.Valid(h) && h <= 0x23
+}
@@ -0,0 +1,65 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// maxIdle - предел Until по умолчанию: Poll догоняет пропущенные моменты
+// не дальше суток.
+const maxIdle = 24 * time.Hour
+
+// Until возвращает, сколько можно спать до ближайшего момента срабатывания
+// заданий или отложенного будильника, по времени RTC. Вместо опроса раз
+// в секунду устройство на батарее спит это время (или уходит в глубокий
+// сон) и вызывает Poll после пробуждения. Результат не превышает MaxIdle
+// (по умолчанию сутки); без заданий возвращается предел.
+func (s *Scheduler) Until() (time.Duration, error) {
+    now, err := s.RTC.now()
+    if err != nil {
+        return 0, err
+    }
+    limit := s.MaxIdle
+    if limit <= 0 || limit > maxIdle {
+        limit = maxIdle
+    }
+    wait := limit
+    for _, j := range s.jobs {
+        if next, ok := s.Next(j.Trigger, now); ok && next.Sub(now) < wait {
+            wait = next.Sub(now)
+        }
+        if j.snooze.After(now) && j.snooze.Sub(now) < wait {
+            wait = j.snooze.Sub(now)
+        }
+    }
+    return wait, nil
+}
+
+// PollSleep - режим пониженного потребления: вызывает Poll, спит функцией
+// sleep (time.Sleep, machine.Sleep или переход в сон с пробуждением по
+// таймеру) до ближайшего момента по Until и вызывает Poll снова:
+//
+//     for {
+//         if err := sch.PollSleep(time.Sleep); err != nil { ... }
+//     }
+//
+// Если таймер МК уходит вперед, задание выполняется с опозданием на эту
+// ошибку; если отстает - следующий PollSleep доспит остаток.
+func (s *Scheduler) PollSleep(sleep func(d time.Duration)) error {
+    if err := s.Poll(); err != nil {
+        return err
+    }
+    d, err := s.Until()
+    if err != nil {
+        return err
+    }
+    sleep(d)
+    return s.Poll()
+}
+
+// Resume задает время прошлого опроса, например сохраненное в ОЗУ перед
+// глубоким сном, после которого МК перезапускается. Тогда первый Poll
+// после запуска выполнит задания, наступившие во время сна, а не только
+// запомнит время.
+func (s *Scheduler) Resume(last time.Time) {
+    s.last = last
+}
@@ -0,0 +1,54 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// Interpolator дополняет время DS1302 долями секунды.
+//
+// Сама микросхема считает только целые секунды. Interpolator запоминает
+// момент (по монотонным часам микроконтроллера), когда регистр секунд
+// сменился, и отсчитывает от него долю текущей секунды. Это позволяет
+// плавно анимировать секундную стрелку или перерисовывать экран
+// сразу после смены секунды.
+type Interpolator struct {
+    dev    *DS1302
+    last   DateTime
+    edge   time.Time
+    synced bool
+}
+
+// NewInterpolator создает Interpolator для устройства d.
+func NewInterpolator(d *DS1302) *Interpolator {
+    return &Interpolator{dev: d}
+}
+
+// Read читает время RTC и возвращает его вместе с долей секунды,
+// прошедшей с момента смены секунды. Пока смена секунды не наблюдалась,
+// доля равна нулю, а synced - false. Для точности вызывайте Read
+// чаще, чем требуемое разрешение. При ошибке чтения возвращается
+// нулевое dt и synced = false, а состояние не меняется.
+func (ip *Interpolator) Read() (dt DateTime, frac time.Duration, synced bool) {
+    dt, err := ip.dev.ReadDateTime()
+    if err != nil {
+        return DateTime{}, 0, false
+    }
+    now := time.Now()
+
+    if dt.Second != ip.last.Second || dt.Minute != ip.last.Minute {
+        if ip.last != (DateTime{}) {
+            ip.edge = now
+            ip.synced = true
+        }
+        ip.last = dt
+    }
+
+    if !ip.synced {
+        return dt, 0, false
+    }
+    frac = now.Sub(ip.edge)
+    if frac >= time.Second {
+        frac = time.Second - 1
+    }
+    return dt, frac, true
+}
@@ -0,0 +1,282 @@
+package ds1302
+
+import (
+    "errors"
+    "io"
+)
+
+// Ошибки хранилища KV.
+var (
+    ErrKVNotFound = errors.New("ds1302: key not found")
+    ErrKVFull     = errors.New("ds1302: key-value store is full")
+    ErrKVCorrupt  = errors.New("ds1302: key-value store is corrupt")
+    ErrKVKey      = errors.New("ds1302: key 0 is reserved")
+)
+
+// Формат области KV: CRC-16 (big-endian) по использованной части, затем
+// записи "ключ (1) | длина (1) | значение" и ключ 0 как признак конца, если
+// осталось место. Длина kvTombstone отмечает удаление ключа. Новая запись
+// дописывается в конец и отменяет прежние с тем же ключом; когда место
+// кончается, живые записи уплотняются и область перезаписывается целиком.
+const (
+    kvHeader    = 2
+    kvRecord    = 2
+    kvEnd       = 0x00
+    kvTombstone = 0xFF
+)
+
+// KV - крошечное хранилище "ключ-значение" в резервном ОЗУ, чтобы
+// несколько подсистем (смещение часового пояса, калибровка, маркеры)
+// делили 31 байт без ручного распределения адресов. Ключи - байты 1-255.
+//
+// Целостность проверяется CRC: если область повреждена (или модуль новый),
+// Get, Set, Delete и Keys возвращают ErrKVCorrupt, пока хранилище не будет
+// явно стерто через Format. Запись дописывается и подтверждается CRC двумя
+// обменами, поэтому сбой питания между ними тоже дает ErrKVCorrupt, а не
+// молчаливую потерю остальных ключей.
+type KV struct {
+    dev  *DS1302
+    addr uint8
+    size uint8
+}
+
+// NewKV создает хранилище в области ОЗУ размером size байт начиная с addr.
+func NewKV(d *DS1302, addr, size uint8) *KV {
+    return &KV{dev: d, addr: addr, size: size}
+}
+
+// kvImage - образ области KV в памяти МК.
+type kvImage struct {
+    buf  [RAMSize]byte
+    used int // Конец последней записи
+}
+
+// load читает и проверяет область. При повреждении возвращает пустой образ
+// вместе с ErrKVCorrupt.
+func (kv *KV) load() (*kvImage, error) {
+    if err := kv.bounds(); err != nil {
+        return nil, err
+    }
+    img := &kvImage{used: kvHeader}
+    raw := img.buf[:kv.size]
+    if err := kv.dev.readRAMBytes(kv.addr, raw); err != nil {
+        return nil, err
+    }
+    if len(raw) <= kvHeader {
+        return kv.empty(), kv.dev.fail(ErrKVCorrupt)
+    }
+    pos := kvHeader
+    for pos < len(raw) && raw[pos] != kvEnd {
+        if pos+kvRecord > len(raw) {
+            return kv.empty(), kv.dev.fail(ErrKVCorrupt)
+        }
+        n := int(raw[pos+1])
+        if n == kvTombstone {
+            n = 0
+        }
+        if pos+kvRecord+n > len(raw) {
+            return kv.empty(), kv.dev.fail(ErrKVCorrupt)
+        }
+        pos += kvRecord + n
+    }
+    if crc16(raw[kvHeader:pos]) != uint16(raw[0])<<8|uint16(raw[1]) {
+        return kv.empty(), kv.dev.fail(ErrKVCorrupt)
+    }
+    img.used = pos
+    return img, nil
+}
+
+// bounds проверяет, что область помещается в ОЗУ.
+func (kv *KV) bounds() error {
+    if int(kv.addr)+int(kv.size) > RAMSize {
+        return kv.dev.fail(ErrRAMOutOfRange)
+    }
+    return nil
+}
+
+func (kv *KV) empty() *kvImage {
+    return &kvImage{used: kvHeader}
+}
+
+// find возвращает позицию последней записи key или -1.
+func (img *kvImage) find(key uint8) int {
+    at := -1
+    for pos := kvHeader; pos < img.used; pos = img.next(pos) {
+        if img.buf[pos] == key {
+            at = pos
+        }
+    }
+    if at >= 0 && img.buf[at+1] == kvTombstone {
+        return -1
+    }
+    return at
+}
+
+// next возвращает позицию записи, следующей за записью в pos.
+func (img *kvImage) next(pos int) int {
+    n := int(img.buf[pos+1])
+    if n == kvTombstone {
+        n = 0
+    }
+    return pos + kvRecord + n
+}
+
+// compact оставляет только живые записи, кроме ключа skip.
+func (img *kvImage) compact(skip uint8) {
+    var out kvImage
+    out.used = kvHeader
+    for pos := kvHeader; pos < img.used; pos = img.next(pos) {
+        key := img.buf[pos]
+        if key == skip || img.find(key) != pos {
+            continue
+        }
+        out.used += copy(out.buf[out.used:], img.buf[pos:img.next(pos)])
+    }
+    *img = out
+}
+
+// Get копирует значение key в buf и возвращает его длину.
+// Если buf короче значения, возвращает io.ErrShortBuffer.
+func (kv *KV) Get(key uint8, buf []byte) (int, error) {
+    kv.dev.mu.Lock()
+    defer kv.dev.mu.Unlock()
+    img, err := kv.load()
+    if err != nil {
+        return 0, err
+    }
+    pos := img.find(key)
+    if pos < 0 {
+        return 0, ErrKVNotFound
+    }
+    n := int(img.buf[pos+1])
+    if len(buf) < n {
+        return 0, io.ErrShortBuffer
+    }
+    return copy(buf, img.buf[pos+kvRecord:pos+kvRecord+n]), nil
+}
+
+// Set сохраняет value под ключом key. Если значение не изменилось,
+// запись в ОЗУ не выполняется.
+func (kv *KV) Set(key uint8, value []byte) error {
+    if key == kvEnd {
+        return ErrKVKey
+    }
+    if len(value) >= kvTombstone {
+        return ErrKVFull
+    }
+    kv.dev.mu.Lock()
+    defer kv.dev.mu.Unlock()
+    img, err := kv.load()
+    if err != nil {
+        return err
+    }
+    if pos := img.find(key); pos >= 0 && string(img.buf[pos+kvRecord:img.next(pos)]) == string(value) {
+        return nil
+    }
+    return kv.append(img, key, value, false)
+}
+
+// Delete удаляет key. Отсутствующий ключ возвращает ErrKVNotFound.
+func (kv *KV) Delete(key uint8) error {
+    kv.dev.mu.Lock()
+    defer kv.dev.mu.Unlock()
+    img, err := kv.load()
+    if err != nil {
+        return err
+    }
+    if img.find(key) < 0 {
+        return ErrKVNotFound
+    }
+    return kv.append(img, key, nil, true)
+}
+
+// Keys возвращает живые ключи в порядке последней записи.
+func (kv *KV) Keys() ([]uint8, error) {
+    kv.dev.mu.Lock()
+    defer kv.dev.mu.Unlock()
+    img, err := kv.load()
+    if err != nil {
+        return nil, err
+    }
+    var keys []uint8
+    for pos := kvHeader; pos < img.used; pos = img.next(pos) {
+        if key := img.buf[pos]; img.find(key) == pos {
+            keys = append(keys, key)
+        }
+    }
+    return keys, nil
+}
+
+// Format стирает хранилище.
+func (kv *KV) Format() error {
+    kv.dev.mu.Lock()
+    defer kv.dev.mu.Unlock()
+    if err := kv.bounds(); err != nil {
+        return err
+    }
+    return kv.flush(kv.empty())
+}
+
+// append дописывает запись (или отметку удаления) и обновляет CRC.
+// Если места нет, образ уплотняется и записывается целиком.
+func (kv *KV) append(img *kvImage, key uint8, value []byte, tombstone bool) error {
+    size := int(kv.size)
+    need := kvRecord + len(value)
+    if img.used+need > size {
+        img.compact(key)
+        if tombstone {
+            return kv.flush(img)
+        }
+        if img.used+need > size {
+            return kv.dev.fail(ErrKVFull)
+        }
+        kv.put(img, key, value, tombstone)
+        return kv.flush(img)
+    }
+
+    start := img.used
+    kv.put(img, key, value, tombstone)
+    if err := kv.dev.checkOpen(); err != nil {
+        return err
+    }
+    kv.dev.writeRegister(DS1302_WP_WRITE, 0x00)
+    kv.dev.writeRAM(kv.addr+uint8(start), img.buf[start:kv.tail(img)])
+    kv.dev.writeRAM(kv.addr, img.buf[:kvHeader])
+    kv.dev.writeRegister(DS1302_WP_WRITE, 0x80)
+    return nil
+}
+
+// put добавляет запись в образ, ставит признак конца и пересчитывает CRC.
+func (kv *KV) put(img *kvImage, key uint8, value []byte, tombstone bool) {
+    img.buf[img.used] = key
+    if tombstone {
+        img.buf[img.used+1] = kvTombstone
+    } else {
+        img.buf[img.used+1] = uint8(len(value))
+    }
+    img.used += kvRecord + copy(img.buf[img.used+kvRecord:], value)
+    kv.seal(img)
+}
+
+// seal ставит признак конца и записывает CRC в заголовок образа.
+func (kv *KV) seal(img *kvImage) {
+    if img.used < int(kv.size) {
+        img.buf[img.used] = kvEnd
+    }
+    crc := crc16(img.buf[kvHeader:img.used])
+    img.buf[0], img.buf[1] = byte(crc>>8), byte(crc)
+}
+
+// tail возвращает конец данных для записи: записи и признак конца.
+func (kv *KV) tail(img *kvImage) int {
+    if img.used < int(kv.size) {
+        return img.used + 1
+    }
+    return img.used
+}
+
+// flush записывает образ целиком.
+func (kv *KV) flush(img *kvImage) error {
+    kv.seal(img)
+    return kv.dev.writeRAMBytes(kv.addr, img.buf[:kv.tail(img)])
+}
@@ -0,0 +1,74 @@
+package ds1302_test
+
+import (
+    "bytes"
+    "errors"
+    "testing"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+)
+
+// TestKVInterruptedAppend проверяет сбой питания между записью новой записи
+// и обновлением CRC: хранилище должно сообщить ErrKVCorrupt и не стирать
+// остальные ключи, пока его не отформатируют явно.
+func TestKVInterruptedAppend(t *testing.T) {
+    rtc, _, _ := newTestRTC(t, ds1302.Hour24)
+    kv := ds1302.NewKV(rtc, 0, ds1302.RAMSize)
+    if err := kv.Format(); err != nil {
+        t.Fatalf("Format: %v", err)
+    }
+    if err := kv.Set(1, []byte{0x11}); err != nil {
+        t.Fatalf("Set(1): %v", err)
+    }
+
+    // Запоминаем заголовок до дозаписи и возвращаем его после: запись
+    // ключа 2 легла в ОЗУ, а CRC - нет.
+    var header [2]byte
+    if err := rtc.ReadRAMBytes(0, header[:]); err != nil {
+        t.Fatalf("ReadRAMBytes: %v", err)
+    }
+    if err := kv.Set(2, []byte{0x22, 0x23}); err != nil {
+        t.Fatalf("Set(2): %v", err)
+    }
+    if err := rtc.WriteRAMBytes(0, header[:]); err != nil {
+        t.Fatalf("WriteRAMBytes: %v", err)
+    }
+    var before [ds1302.RAMSize]byte
+    if err := rtc.ReadRAMBytes(0, before[:]); err != nil {
+        t.Fatalf("ReadRAMBytes: %v", err)
+    }
+
+    var buf [4]byte
+    if _, err := kv.Get(1, buf[:]); !errors.Is(err, ds1302.ErrKVCorrupt) {
+        t.Errorf("Get after interrupted append: err = %v, want ErrKVCorrupt", err)
+    }
+    if _, err := kv.Keys(); !errors.Is(err, ds1302.ErrKVCorrupt) {
+        t.Errorf("Keys after interrupted append: err = %v, want ErrKVCorrupt", err)
+    }
+    if err := kv.Set(3, []byte{0x33}); !errors.Is(err, ds1302.ErrKVCorrupt) {
+        t.Errorf("Set after interrupted append: err = %v, want ErrKVCorrupt", err)
+    }
+    if err := kv.Delete(1); !errors.Is(err, ds1302.ErrKVCorrupt) {
+        t.Errorf("Delete after interrupted append: err = %v, want ErrKVCorrupt", err)
+    }
+    var after [ds1302.RAMSize]byte
+    if err := rtc.ReadRAMBytes(0, after[:]); err != nil {
+        t.Fatalf("ReadRAMBytes: %v", err)
+    }
+    if !bytes.Equal(before[:], after[:]) {
+        t.Errorf("corrupt store was rewritten:\nbefore % x\nafter  % x", before, after)
+    }
+
+    if err := kv.Format(); err != nil {
+        t.Fatalf("Format: %v", err)
+    }
+    if err := kv.Set(3, []byte{0x33}); err != nil {
+        t.Fatalf("Set after Format: %v", err)
+    }
+    if _, err := kv.Get(1, buf[:]); !errors.Is(err, ds1302.ErrKVNotFound) {
+        t.Errorf("Get(1) after Format: err = %v, want ErrKVNotFound", err)
+    }
+    if n, err := kv.Get(3, buf[:]); err != nil || !bytes.Equal(buf[:n], []byte{0x33}) {
+        t.Errorf("Get(3) = % x, %v, want 33", buf[:n], err)
+    }
+}
@@ -0,0 +1,117 @@
+package ds1302
+
+import (
+    "context"
+    "errors"
+)
+
+// Ошибки проверки версии раскладки ОЗУ.
+var (
+    ErrLayoutTooNew = errors.New("ds1302: RAM layout is newer than firmware")
+    ErrNoMigration  = errors.New("ds1302: no RAM layout migration for stored version")
+)
+
+// LayoutHeaderSize - размер заголовка раскладки ОЗУ: версия и проверочный байт.
+const LayoutHeaderSize = 2
+
+// layoutMagic маскирует проверочный байт заголовка раскладки.
+const layoutMagic = 0x96
+
+// Migration переводит образ всего ОЗУ (RAMSize байт) из одной версии
+// раскладки в следующую. Заголовок раскладки в образе изменять не нужно.
+type Migration func(ram []byte) error
+
+// Layout описывает версию раскладки резервного ОЗУ прошивки - где лежат
+// счетчик запусков, настройки, журналы - и как обновить старые раскладки.
+//
+//     layout := ds1302.Layout{
+//         Addr:    0,
+//         Version: 2,
+//         Migrations: map[uint8]ds1302.Migration{
+//             // v1 -> v2: счетчик запусков переехал с 2 на 4.
+//             1: func(ram []byte) error {
+//                 copy(ram[4:8], ram[2:6])
+//                 return nil
+//             },
+//         },
+//     }
+//     if _, err := rtc.ApplyLayout(layout); err != nil { ... }
+type Layout struct {
+    Addr       uint8               // Адрес заголовка (LayoutHeaderSize байт)
+    Version    uint8               // Текущая версия раскладки (1-255)
+    Migrations map[uint8]Migration // Переходы: ключ - версия, из которой выполняется переход
+
+    // Init заполняет образ ОЗУ нового модуля (или модуля, потерявшего
+    // питание). nil - обнулить все ОЗУ, кроме заголовка.
+    Init func(ram []byte)
+}
+
+// ApplyLayout приводит резервное ОЗУ к раскладке l и возвращает версию,
+// найденную на модуле (0 - заголовка не было). Вызывается при запуске
+// до обращений к подсистемам, хранящим данные в ОЗУ.
+//
+// Если сохранена более старая версия, миграции применяются по очереди
+// к образу ОЗУ в памяти МК, и результат записывается одной пакетной операцией.
+// Перед записью заголовок стирается, поэтому прерванная миграция при
+// следующем запуске приводит к инициализации, а не к неверному чтению
+// полуобновленных данных. Более новая версия (откат прошивки) не
+// изменяется и возвращает ErrLayoutTooNew.
+func (d *DS1302) ApplyLayout(l Layout) (uint8, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return 0, err
+    }
+    if int(l.Addr)+LayoutHeaderSize > RAMSize {
+        return 0, d.fail(ErrRAMOutOfRange)
+    }
+    
+    var ram [RAMSize]byte
+    d.readBurst(RAMBurstRead, ram[:])
+    hdr := ram[l.Addr : l.Addr+LayoutHeaderSize]
+    
+    stored := hdr[0]
+    if stored == 0 || hdr[1] != stored^layoutMagic {
+        stored = 0
+    }
+    switch {
+    case stored == l.Version:
+        return stored, nil
+    case stored > l.Version:
+        return stored, d.fail(ErrLayoutTooNew)
+    case stored == 0:
+        ram = [RAMSize]byte{}
+        if l.Init != nil {
+            l.Init(ram[:])
+        }
+    default:
+        for v := stored; v < l.Version; v++ {
+            m := l.Migrations[v]
+            if m == nil {
+                return stored, d.fail(ErrNoMigration)
+            }
+            if err := m(ram[:]); err != nil {
+                return stored, err
+            }
+        }
+    }
+    
+    // Сначала стираем заголовок, затем пишем данные одной пакетной
+    // операцией (заголовок в образе нулевой) и новый заголовок.
+    if err := d.writeRAMBytes(l.Addr, make([]byte, LayoutHeaderSize)); err != nil {
+        return stored, err
+    }
+    hdr[0], hdr[1] = 0, 0
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    err := d.writeBurstContext(context.Background(), RAMBurstWrite, ram[:])
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    if err != nil {
+        return stored, err
+    }
+    if d.ramUsage != nil {
+        for i := range ram {
+            d.ramUsage[i]++
+        }
+    }
+    return stored, d.writeRAMBytes(l.Addr, []byte{l.Version, l.Version ^ layoutMagic})
+}
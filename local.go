@@ -0,0 +1,116 @@
+package ds1302
+
+import (
+    "errors"
+    "time"
+)
+
+// Ошибки работы с местным смещением.
+var (
+    ErrInvalidOffset       = errors.New("ds1302: local offset must be a multiple of 15 minutes within ±14h")
+    ErrNoLocalOffset       = errors.New("ds1302: local offset is not set")
+    ErrLocalOffsetDisabled = errors.New("ds1302: local offset is not enabled")
+)
+
+// LocalOffsetSize - размер местного смещения в ОЗУ: значение и проверочный байт.
+const LocalOffsetSize = 2
+
+// Смещение хранится в четвертях часа, чтобы покрыть пояса вроде UTC+5:45.
+const (
+    offsetUnit  = 15 * time.Minute
+    offsetMax   = 14 * time.Hour
+    offsetMagic = 0x5A
+)
+
+// EnableLocalOffset указывает адрес в резервном ОЗУ, где хранится смещение
+// местного времени от UTC (LocalOffsetSize байт). Часы при этом всегда
+// идут по UTC: журналы пишутся в UTC, а на экран выводится ReadTimeLocal,
+// и приложению не нужно самому пересчитывать время.
+func (d *DS1302) EnableLocalOffset(addr uint8) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if int(addr)+LocalOffsetSize > RAMSize {
+        return d.fail(ErrRAMOutOfRange)
+    }
+    d.localAddr = addr
+    d.localEnabled = true
+    return nil
+}
+
+// SetLocalOffset сохраняет смещение местного времени от UTC,
+// например 3*time.Hour для Москвы. Смещение должно быть кратно
+// 15 минутам и не превышать 14 часов по модулю.
+func (d *DS1302) SetLocalOffset(offset time.Duration) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if !d.localEnabled {
+        return d.fail(ErrLocalOffsetDisabled)
+    }
+    if offset%offsetUnit != 0 || offset > offsetMax || offset < -offsetMax {
+        return d.fail(ErrInvalidOffset)
+    }
+    v := byte(int8(offset / offsetUnit))
+    return d.writeRAMBytes(d.localAddr, []byte{v, v ^ offsetMagic})
+}
+
+// LocalOffset возвращает сохраненное смещение местного времени от UTC;
+// для часового пояса (SetLocalZone) - смещение на текущий момент по RTC.
+// Если смещение не записывалось или ОЗУ потеряло содержимое,
+// возвращается ErrNoLocalOffset.
+func (d *DS1302) LocalOffset() (time.Duration, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.localOffset()
+}
+
+func (d *DS1302) localOffset() (time.Duration, error) {
+    loc, err := d.localLocation()
+    if err != nil {
+        return 0, err
+    }
+    t, _ := d.readTime()
+    _, offset := t.In(loc).Zone()
+    return time.Duration(offset) * time.Second, nil
+}
+
+// localLocation читает из ОЗУ смещение или часовой пояс (см. SetLocalZone).
+func (d *DS1302) localLocation() (*time.Location, error) {
+    if !d.localEnabled {
+        return nil, d.fail(ErrLocalOffsetDisabled)
+    }
+    var buf [LocalOffsetSize]byte
+    if err := d.readRAMBytes(d.localAddr, buf[:]); err != nil {
+        return nil, err
+    }
+    if buf[1] == buf[0]^zoneMagic {
+        loc, ok := zoneLocation(buf[0])
+        if !ok {
+            return nil, ErrUnknownZone
+        }
+        return loc, nil
+    }
+    offset := time.Duration(int8(buf[0])) * offsetUnit
+    if buf[1] != buf[0]^offsetMagic || offset > offsetMax || offset < -offsetMax {
+        return nil, ErrNoLocalOffset
+    }
+    return time.FixedZone("", int(offset/time.Second)), nil
+}
+
+// ReadTimeUTC читает время часов, которые идут по UTC.
+func (d *DS1302) ReadTimeUTC() time.Time {
+    return d.ReadTime()
+}
+
+// ReadTimeLocal читает время часов и переводит его в местное по смещению
+// или часовому поясу из ОЗУ. Если они недоступны, возвращает время в UTC
+// вместе с ошибкой, так что экран продолжает показывать хотя бы UTC.
+func (d *DS1302) ReadTimeLocal() (time.Time, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    t, _ := d.readTime()
+    loc, err := d.localLocation()
+    if err != nil {
+        return t, err
+    }
+    return t.In(loc), nil
+}
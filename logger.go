@@ -0,0 +1,9 @@
+package ds1302
+
+// Logger - приемник отладочных сообщений драйвера (см. Config.Logger).
+// Интерфейс совместим с распространенными логгерами; для log.Logger
+// достаточно тонкой обертки над Printf.
+type Logger interface {
+    Debugf(format string, args ...any) // Каждая транзакция на шине
+    Errorf(format string, args ...any) // Каждая ошибка, учтенная в Metrics
+}
@@ -0,0 +1,111 @@
+package ds1302
+
+import (
+    "encoding/binary"
+    "time"
+)
+
+// MailboxSize - число байт ОЗУ, занимаемых Mailbox: время (4), код (2),
+// аргумент (2) и CRC-16 (2).
+const MailboxSize = 10
+
+// Message - сообщение следующему запуску.
+type Message struct {
+    Time time.Time // Время RTC отправки
+    Code uint16    // Код причины, например сброс сторожевым таймером
+    Arg  uint16    // Дополнительное значение: код ошибки, номер задачи
+}
+
+// Mailbox - почтовый ящик на одно сообщение в резервном ОЗУ: прошивка
+// оставляет код перед сбросом ("сторожевой таймер в 03:12, ошибка 0x17"),
+// а следующий запуск забирает его и, например, отправляет в журнал.
+//
+// Область ящика выделяется в раскладке ОЗУ (см. Layout): пустой ящик -
+// любые данные с неверной CRC, в том числе нули, которыми ApplyLayout
+// заполняет ОЗУ нового модуля. При смене адреса ящика в новой версии
+// раскладки сообщение переносит миграция MoveMailbox.
+type Mailbox struct {
+    dev  *DS1302
+    addr uint8
+}
+
+// NewMailbox создает почтовый ящик в ОЗУ по адресу addr.
+func NewMailbox(d *DS1302, addr uint8) *Mailbox {
+    return &Mailbox{dev: d, addr: addr}
+}
+
+// Post оставляет сообщение с кодом code и аргументом arg, отмечая его
+// временем RTC. Прежнее непрочитанное сообщение заменяется.
+func (m *Mailbox) Post(code, arg uint16) error {
+    m.dev.mu.Lock()
+    defer m.dev.mu.Unlock()
+    now, err := m.dev.readTime()
+    if err != nil {
+        return err
+    }
+    var buf [MailboxSize]byte
+    binary.LittleEndian.PutUint32(buf[0:], Pack(now))
+    binary.LittleEndian.PutUint16(buf[4:], code)
+    binary.LittleEndian.PutUint16(buf[6:], arg)
+    crc := crc16(buf[:MailboxSize-2])
+    buf[8], buf[9] = byte(crc>>8), byte(crc)
+    return m.dev.writeRAMBytes(m.addr, buf[:])
+}
+
+// Peek возвращает сообщение, не удаляя его. ok равно false, если ящик пуст.
+func (m *Mailbox) Peek() (msg Message, ok bool, err error) {
+    m.dev.mu.Lock()
+    defer m.dev.mu.Unlock()
+    return m.peek()
+}
+
+// Take возвращает сообщение и очищает ящик, так что каждое сообщение
+// читается одним запуском:
+//
+//     if msg, ok, _ := box.Take(); ok {
+//         log.Printf("reset %v code=%#x arg=%d", msg.Time, msg.Code, msg.Arg)
+//     }
+func (m *Mailbox) Take() (msg Message, ok bool, err error) {
+    m.dev.mu.Lock()
+    defer m.dev.mu.Unlock()
+    msg, ok, err = m.peek()
+    if !ok || err != nil {
+        return msg, ok, err
+    }
+    return msg, true, m.dev.writeRAMBytes(m.addr, make([]byte, MailboxSize))
+}
+
+func (m *Mailbox) peek() (Message, bool, error) {
+    var buf [MailboxSize]byte
+    if err := m.dev.readRAMBytes(m.addr, buf[:]); err != nil {
+        return Message{}, false, err
+    }
+    crc := crc16(buf[:MailboxSize-2])
+    if buf[8] != byte(crc>>8) || buf[9] != byte(crc) {
+        return Message{}, false, nil
+    }
+    return Message{
+        Time: Unpack(binary.LittleEndian.Uint32(buf[0:])),
+        Code: binary.LittleEndian.Uint16(buf[4:]),
+        Arg:  binary.LittleEndian.Uint16(buf[6:]),
+    }, true, nil
+}
+
+// MoveMailbox возвращает миграцию раскладки, переносящую ящик с адреса
+// from на адрес to:
+//
+//     Migrations: map[uint8]ds1302.Migration{1: ds1302.MoveMailbox(20, 4)},
+func MoveMailbox(from, to uint8) Migration {
+    return func(ram []byte) error {
+        if int(from)+MailboxSize > len(ram) || int(to)+MailboxSize > len(ram) {
+            return ErrRAMOutOfRange
+        }
+        var msg [MailboxSize]byte
+        copy(msg[:], ram[from:])
+        for i := range msg {
+            ram[int(from)+i] = 0
+        }
+        copy(ram[to:], msg[:])
+        return nil
+    }
+}
@@ -0,0 +1,49 @@
+package ds1302
+
+// MarkerAddr - адрес байтов ОЗУ, зарезервированных под маркер состояния.
+// Маркер занимает два последних байта ОЗУ: значение и проверочный байт.
+const MarkerAddr = RAMSize - 2
+
+// markerMagic - шаблон, которым маскируется проверочный байт маркера.
+// Случайное содержимое ОЗУ нового модуля совпадет с ним с вероятностью 1/256.
+const markerMagic = 0xA5
+
+// Рекомендуемые значения маркера.
+const (
+    MarkerRunning  = 0x01 // Прошивка работает
+    MarkerShutdown = 0x02 // Прошивка корректно завершила работу
+)
+
+// SetMarker записывает однобайтовый маркер состояния в зарезервированные байты ОЗУ.
+//
+// Типичное использование - признак корректного завершения работы:
+//
+//     m, ok := rtc.Marker()
+//     switch {
+//     case !ok:                        // новый модуль или пропадало питание батареи
+//     case m == ds1302.MarkerRunning:  // прошлый запуск завершился аварийно
+//     }
+//     rtc.SetMarker(ds1302.MarkerRunning)
+//     ...
+//     rtc.SetMarker(ds1302.MarkerShutdown)
+func (d *DS1302) SetMarker(value byte) error {
+    return d.WriteRAMBytes(MarkerAddr, []byte{value, value ^ markerMagic})
+}
+
+// Marker возвращает маркер состояния. ok равно false, если маркер
+// не записывался (новый модуль) или ОЗУ потеряло содержимое.
+func (d *DS1302) Marker() (value byte, ok bool) {
+    var buf [2]byte
+    if err := d.ReadRAMBytes(MarkerAddr, buf[:]); err != nil {
+        return 0, false
+    }
+    if buf[1] != buf[0]^markerMagic {
+        return 0, false
+    }
+    return buf[0], true
+}
+
+// ClearMarker стирает маркер, после чего Marker возвращает ok = false.
+func (d *DS1302) ClearMarker() error {
+    return d.WriteRAMBytes(MarkerAddr, []byte{0, 0})
+}
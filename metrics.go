@@ -0,0 +1,57 @@
+package ds1302
+
+import (
+    "github.com/golangworker/ds1302-driver/codec"
+)
+
+// ErrInvalidBCD возвращается, если регистр часов содержит недопустимое BCD значение.
+// Обычно это признак плохого контакта, помех на линии или неисправного модуля.
+var ErrInvalidBCD = codec.ErrInvalidBCD
+
+// Metrics - снимок счетчиков состояния драйвера.
+// Позволяет долго работающим устройствам передавать здоровье RTC
+// в телеметрию и выявлять ненадежную проводку в поле.
+type Metrics struct {
+    Transactions       uint32 // Число транзакций на шине (запись или чтение регистра)
+    Retries            uint32 // Число повторных попыток обмена
+    ValidationFailures uint32 // Число данных, не прошедших проверку (BCD, CRC, контрольное чтение)
+    Errors             uint32 // Общее число ошибок
+    BusResets          uint32 // Число сбросов шины (см. ResetBus)
+    LastError          error  // Последняя ошибка или nil
+}
+
+// Metrics возвращает снимок счетчиков состояния драйвера.
+func (d *DS1302) Metrics() Metrics {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.metrics
+}
+
+// fail учитывает ошибку в счетчиках и возвращает ее.
+func (d *DS1302) fail(err error) error {
+    if err != nil {
+        d.metrics.Errors++
+        d.metrics.LastError = err
+        if validationErr(err) {
+            d.metrics.ValidationFailures++
+        }
+        if l := d.cfg.Logger; l != nil {
+            l.Errorf("%v", err)
+        }
+        if desync(err) {
+            d.resetBus()
+        }
+    }
+    return err
+}
+
+// validationErr сообщает, что err - результат проверки данных
+// микросхемы или ОЗУ (BCD, CRC, контрольное чтение), а не ошибка обмена.
+func validationErr(err error) bool {
+    switch err {
+    case ErrInvalidBCD, ErrVerifyFailed, ErrStoreCorrupt, ErrKVCorrupt,
+        ErrBadSnapshot, ErrAlarmsCorrupt:
+        return true
+    }
+    return false
+}
@@ -0,0 +1,110 @@
+// Package metricshttp отдает состояние DS1302 по HTTP в текстовом формате
+// Prometheus, чтобы операторы парка устройств получали оповещения
+// о плохих кварцах и севших батареях резервного питания:
+//
+//     http.Handle("/metrics", &metricshttp.Handler{RTC: rtc, Syncer: &syncer})
+//
+// Пакет отдельный, чтобы прошивки без сети не тянули net/http.
+package metricshttp
+
+import (
+    "net/http"
+    "strconv"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+)
+
+// ContentType - тип содержимого текстового формата Prometheus.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler - http.Handler с метриками:
+//
+//   - ds1302_running - генератор работает (0 - стоит, например после потери питания);
+//   - ds1302_time_valid - регистры часов содержат допустимое время;
+//   - ds1302_time_seconds - время RTC в секундах Unix;
+//   - ds1302_trust_level - ds1302.TrustLevel: 0 неверно, 1 устарело, 2 доверенное;
+//   - ds1302_bus_transactions_total, ds1302_bus_retries_total,
+//     ds1302_validation_failures_total, ds1302_errors_total - счетчики ds1302.Metrics;
+//   - ds1302_drift_seconds - RTC минус эталон при последней синхронизации
+//     (положительное - часы спешат);
+//   - ds1302_last_sync_age_seconds - сколько прошло по RTC с последней синхронизации.
+//
+// Метрики синхронизации выводятся, только если задан Syncer и синхронизация
+// уже выполнялась. Каждый запрос обращается к шине.
+type Handler struct {
+    RTC    *ds1302.DS1302
+    Syncer *ds1302.Syncer // Необязательный источник дрейфа и возраста синхронизации
+}
+
+// ServeHTTP отвечает текущими значениями метрик.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", ContentType)
+    w.Write(h.Append(make([]byte, 0, 1024)))
+}
+
+// Append добавляет метрики к dst, например для отправки без net/http.
+func (h *Handler) Append(dst []byte) []byte {
+    st, err := h.RTC.Status()
+    dst = gauge(dst, "ds1302_running", "Oscillator is running (CH bit clear).", boolValue(st.Running))
+    dst = gauge(dst, "ds1302_time_valid", "Clock registers hold a valid BCD time.", boolValue(err == nil))
+    if err == nil {
+        dst = gauge(dst, "ds1302_time_seconds", "RTC time in seconds since the Unix epoch.", float64(st.Time.Unix()))
+    }
+    dst = gauge(dst, "ds1302_trust_level", "Trust in RTC time: 0 invalid, 1 stale, 2 trusted.", float64(h.RTC.Trust()))
+
+    m := h.RTC.Metrics()
+    dst = counter(dst, "ds1302_bus_transactions_total", "Bus transactions.", m.Transactions)
+    dst = counter(dst, "ds1302_bus_retries_total", "Repeated bus reads.", m.Retries)
+    dst = counter(dst, "ds1302_bus_resets_total", "Bus reset sequences.", m.BusResets)
+    dst = counter(dst, "ds1302_validation_failures_total", "Values read from the chip that failed validation.", m.ValidationFailures)
+    dst = counter(dst, "ds1302_errors_total", "Driver errors.", m.Errors)
+
+    if h.Syncer == nil {
+        return dst
+    }
+    last, ok := h.Syncer.LastSync()
+    if !ok {
+        return dst
+    }
+    dst = gauge(dst, "ds1302_drift_seconds", "RTC minus reference time at the last sync.", (-h.Syncer.LastOffset()).Seconds())
+    if err == nil {
+        dst = gauge(dst, "ds1302_last_sync_age_seconds", "RTC time elapsed since the last sync.", st.Time.Sub(last).Seconds())
+    }
+    return dst
+}
+
+func gauge(dst []byte, name, help string, v float64) []byte {
+    dst = header(dst, name, help, "gauge")
+    return sample(dst, name, v)
+}
+
+func counter(dst []byte, name, help string, v uint32) []byte {
+    dst = header(dst, name, help, "counter")
+    return sample(dst, name, float64(v))
+}
+
+func header(dst []byte, name, help, typ string) []byte {
+    dst = append(dst, "# HELP "...)
+    dst = append(dst, name...)
+    dst = append(dst, ' ')
+    dst = append(dst, help...)
+    dst = append(dst, "\n# TYPE "...)
+    dst = append(dst, name...)
+    dst = append(dst, ' ')
+    dst = append(dst, typ...)
+    return append(dst, '\n')
+}
+
+func sample(dst []byte, name string, v float64) []byte {
+    dst = append(dst, name...)
+    dst = append(dst, ' ')
+    dst = strconv.AppendFloat(dst, v, 'f', -1, 64)
+    return append(dst, '\n')
+}
+
+func boolValue(b bool) float64 {
+    if b {
+        return 1
+    }
+    return 0
+}
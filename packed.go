@@ -0,0 +1,45 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// PackedSize - размер упакованной метки времени в ОЗУ.
+const PackedSize = 4
+
+// Диапазон упакованных меток времени (UTC).
+var (
+    PackedMin = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+    PackedMax = time.Date(2127, 12, 31, 23, 59, 59, 0, time.UTC)
+)
+
+// Pack упаковывает t в uint32: число секунд от PackedMin. Формат
+// помещается в 4 байта ОЗУ вместо 7 регистров часов, и упакованные метки
+// можно сравнивать и вычитать как числа. Время вне диапазона
+// PackedMin-PackedMax приводится к ближайшей границе, доли секунды
+// отбрасываются.
+//
+// В ОЗУ драйвер хранит упакованные метки в порядке little-endian
+// (журнал перестановок, сроки, почтовый ящик, контроль вмешательства);
+// значение 0 (PackedMin) там означает "метки нет".
+func Pack(t time.Time) uint32 {
+    switch {
+    case !t.After(PackedMin):
+        return 0
+    case t.After(PackedMax):
+        t = PackedMax
+    }
+    return uint32(t.Sub(PackedMin) / time.Second)
+}
+
+// Unpack восстанавливает время UTC из значения Pack. Значения за
+// PackedMax, которые Pack не выдает, приводятся к PackedMax.
+func Unpack(v uint32) time.Time {
+    if v > packedMax {
+        v = packedMax
+    }
+    return PackedMin.Add(time.Duration(v) * time.Second)
+}
+
+// packedMax - Pack(PackedMax).
+var packedMax = uint32(PackedMax.Sub(PackedMin) / time.Second)
@@ -0,0 +1,50 @@
+package ds1302
+
+import (
+    "errors"
+)
+
+// Pin описывает линию GPIO, через которую драйвер управляет DS1302.
+//
+// Под TinyGo драйвер сам оборачивает machine.Pin (см. NewDS1302).
+// Для других платформ (одноплатные компьютеры, gobot, мосты USB-GPIO)
+// достаточно реализовать этот интерфейс и передать пины в New.
+type Pin interface {
+    ConfigureOutput() // Перевести линию в режим выхода
+    ConfigureInput()  // Перевести линию в режим входа
+    High()            // Установить высокий уровень
+    Low()             // Установить низкий уровень
+    Get() bool        // Прочитать уровень линии
+}
+
+// Ошибки назначения линий (см. PinError).
+var (
+    ErrNoPin        = errors.New("pin is not assigned")
+    ErrDuplicatePin = errors.New("pin is assigned to another line")
+    ErrPinNotInput  = errors.New("pin cannot be used as input")
+    ErrPinNotOutput = errors.New("pin cannot be used as output")
+)
+
+// PinError описывает неверно назначенную линию: Line - "CLK", "DAT" или "RST",
+// Err - одна из ошибок ErrNoPin, ErrDuplicatePin, ErrPinNotInput, ErrPinNotOutput.
+type PinError struct {
+    Line string
+    Err  error
+}
+
+func (e *PinError) Error() string {
+    return "ds1302: " + e.Line + " " + e.Err.Error()
+}
+
+func (e *PinError) Unwrap() error {
+    return e.Err
+}
+
+// PinError возвращает ошибку назначения линий, найденную при создании
+// устройства (см. NewDS1302), или nil. Пока она не nil, Init не трогает
+// линии, а методы возвращают ее вместо чтения нулей с неподключенной шины.
+func (d *DS1302) PinError() error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return d.pinErr
+}
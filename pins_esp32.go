@@ -0,0 +1,19 @@
+//go:build tinygo && esp32
+
+package ds1302
+
+import (
+    "machine"
+)
+
+// pinCaps возвращает возможности вывода ESP32: GPIO6-11 заняты SPI-флешем,
+// GPIO34-39 работают только на ввод, номеров 20, 24 и 28-31 нет.
+func pinCaps(p machine.Pin) (input, output bool) {
+    switch {
+    case p > 39, p >= 6 && p <= 11, p == 20, p == 24, p >= 28 && p <= 31:
+        return false, false
+    case p >= 34:
+        return true, false
+    }
+    return true, true
+}
@@ -0,0 +1,12 @@
+//go:build tinygo && !esp32 && !rp2040
+
+package ds1302
+
+import (
+    "machine"
+)
+
+// pinCaps для плат без известных ограничений считает вывод пригодным.
+func pinCaps(p machine.Pin) (input, output bool) {
+    return true, true
+}
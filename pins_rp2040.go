@@ -0,0 +1,13 @@
+//go:build tinygo && rp2040
+
+package ds1302
+
+import (
+    "machine"
+)
+
+// pinCaps возвращает возможности вывода RP2040: доступны GPIO0-29.
+func pinCaps(p machine.Pin) (input, output bool) {
+    ok := p <= 29
+    return ok, ok
+}
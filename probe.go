@@ -0,0 +1,159 @@
+package ds1302
+
+import (
+    "errors"
+    "strconv"
+)
+
+// ErrNoChip возвращается Probe, если регистры микросхемы не отвечают.
+var ErrNoChip = errors.New("ds1302: no chip responds on the bus")
+
+// probeTrickle - тестовое значение регистра подзарядки. Поле TCS не равно
+// 1010, поэтому подзарядка при проверке не включается.
+const probeTrickle = 0x53
+
+// ProbeReport - структурированный отчет Probe для журнала производства.
+//
+// Флаги No* и StrayBits - эвристические признаки клонов DS1302: оригинал
+// реализует ОЗУ, пакетный обмен, защиту от записи и регистр подзарядки,
+// а неиспользуемые биты регистров читает нулями.
+type ProbeReport struct {
+    Present          bool     // Регистр защиты от записи принимает и возвращает значения
+    DATCLKShort      bool     // DAT повторяет уровень CLK (см. Report)
+    Halted           bool     // Генератор остановлен (бит CH установлен)
+    SecondsAdvancing bool     // Регистр секунд сменился за время наблюдения
+    Seconds          [2]uint8 // Регистр секунд (BCD) в начале и в конце наблюдения
+    RAMFaults        uint32   // Бит n установлен, если байт ОЗУ n не прошел TestRAM
+
+    NoRAM          bool // Ни один байт ОЗУ не сохраняет данные
+    NoBurst        bool // Пакетное чтение ОЗУ расходится с побайтовым
+    NoWriteProtect bool // Запись в ОЗУ проходит при включенной защите
+    NoTrickle      bool // Регистр подзарядки не сохраняет значение
+    StrayBits      bool // Неиспользуемые биты регистров даты читаются единицами
+}
+
+// Suspicious сообщает, есть ли признаки клона.
+func (r ProbeReport) Suspicious() bool {
+    return r.NoRAM || r.NoBurst || r.NoWriteProtect || r.NoTrickle || r.StrayBits
+}
+
+// OK сообщает, что микросхема присутствует, часы идут, ОЗУ исправно
+// и признаков клона нет.
+func (r ProbeReport) OK() bool {
+    return r.Present && !r.DATCLKShort && !r.Halted && r.SecondsAdvancing &&
+        r.RAMFaults == 0 && !r.Suspicious()
+}
+
+// String возвращает многострочный отчет в формате Report.String.
+func (r ProbeReport) String() string {
+    s := "DS1302 probe: "
+    switch {
+    case r.OK():
+        s += "PASS\n"
+    case r.Present && r.Suspicious():
+        s += "SUSPECT CLONE\n"
+    default:
+        s += "FAIL\n"
+    }
+    s += "  present:           " + yesNo(r.Present) + "\n"
+    s += "  dat/clk short:     " + yesNo(r.DATCLKShort) + "\n"
+    s += "  oscillator halted: " + yesNo(r.Halted) + "\n"
+    s += "  seconds advancing: " + yesNo(r.SecondsAdvancing) + "\n"
+    s += "  ram faults:        0x" + strconv.FormatUint(uint64(r.RAMFaults), 16) + "\n"
+    s += "  no ram:            " + yesNo(r.NoRAM) + "\n"
+    s += "  no burst:          " + yesNo(r.NoBurst) + "\n"
+    s += "  no write protect:  " + yesNo(r.NoWriteProtect) + "\n"
+    s += "  no trickle:        " + yesNo(r.NoTrickle) + "\n"
+    s += "  stray bits:        " + yesNo(r.StrayBits) + "\n"
+    return s
+}
+
+// Probe проверяет модуль для входного контроля: отвечает ли микросхема,
+// идут ли часы (около двух секунд наблюдения), исправно ли ОЗУ, и ищет
+// признаки клонов (см. ProbeReport). Время не изменяется, ОЗУ и настройки
+// подзарядки после проверки восстанавливаются; остановленный генератор
+// не запускается.
+//
+// Ошибка возвращается, только если проверку нельзя выполнить: устройство
+// закрыто или микросхема не отвечает (ErrNoChip). Неисправности и признаки
+// клона - в отчете.
+func (d *DS1302) Probe() (ProbeReport, error) {
+    var r ProbeReport
+    d.mu.Lock()
+    if err := d.checkOpen(); err != nil {
+        d.mu.Unlock()
+        return r, err
+    }
+    r.DATCLKShort = d.testDATCLKShort()
+    d.init()
+    r.Present = d.probeRegisters()
+    if !r.Present {
+        defer d.mu.Unlock()
+        return r, d.fail(ErrNoChip)
+    }
+    r.RAMFaults = d.testRAM()
+    r.NoRAM = r.RAMFaults == 1<<RAMSize-1
+    if !r.NoRAM {
+        r.NoBurst = d.probeBurst()
+        r.NoWriteProtect = d.probeWriteProtect()
+    }
+    r.NoTrickle = d.probeTrickle()
+    r.StrayBits = d.readRegister(DS1302_DAY_READ)&0xF8 != 0 ||
+        d.readRegister(DS1302_MONTH_READ)&0xE0 != 0 ||
+        d.readRegister(DS1302_DATE_READ)&0xC0 != 0
+    d.mu.Unlock()
+
+    r.Halted, r.SecondsAdvancing, r.Seconds = d.watchSeconds()
+    return r, nil
+}
+
+// probeRegisters проверяет, что регистр защиты от записи возвращает
+// записанные значения, и оставляет защиту включенной.
+func (d *DS1302) probeRegisters() bool {
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    off := d.readRegister(DS1302_WP_READ)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    on := d.readRegister(DS1302_WP_READ)
+    return off == 0x00 && on == 0x80
+}
+
+// probeBurst сравнивает пакетное чтение ОЗУ с побайтовым.
+func (d *DS1302) probeBurst() bool {
+    var burst [RAMSize]byte
+    d.readBurst(RAMBurstRead, burst[:])
+    for i, v := range burst {
+        if d.readRegister(DS1302_RAM_READ+uint8(i)*2) != v {
+            return true
+        }
+    }
+    return false
+}
+
+// probeWriteProtect пробует изменить байт ОЗУ при включенной защите
+// и восстанавливает его, если запись прошла.
+func (d *DS1302) probeWriteProtect() bool {
+    read := uint8(RAMRead(selfTestAddr))
+    write := uint8(RAMWrite(selfTestAddr))
+    saved := d.readRegister(read)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    d.writeRegister(write, ^saved)
+    if d.readRegister(read) == saved {
+        return false
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    d.writeRegister(write, saved)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    return true
+}
+
+// probeTrickle записывает в регистр подзарядки безопасное тестовое
+// значение и восстанавливает прежнее.
+func (d *DS1302) probeTrickle() bool {
+    saved := d.readRegister(DS1302_TRICKLE_READ)
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    d.writeRegister(DS1302_TRICKLE_WRITE, probeTrickle)
+    got := d.readRegister(DS1302_TRICKLE_READ)
+    d.writeRegister(DS1302_TRICKLE_WRITE, saved)
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    return got != probeTrickle
+}
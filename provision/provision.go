@@ -0,0 +1,215 @@
+// Package provision реализует маленький кадровый протокол для установки
+// времени DS1302 с хоста по USB-serial сразу после прошивки.
+//
+// Формат кадра:
+//
+//     0xA5 | тип (1) | длина (1) | данные (0-16) | CRC-16/CCITT (2, big-endian)
+//
+// CRC считается по полям тип, длина и данные. Время передается
+// как 8 байт секунд Unix и 4 байта наносекунд (big-endian).
+//
+// На стороне прошивки байты из UART передаются в Handler.Feed:
+//
+//     h := provision.Handler{Clock: rtc}
+//     for machine.Serial.Buffered() > 0 {
+//         b, _ := machine.Serial.ReadByte()
+//         h.Feed(b, machine.Serial)
+//     }
+//
+// Хостовая сторона - программа cmd/ds1302sync.
+package provision
+
+import (
+    "encoding/binary"
+    "errors"
+    "io"
+    "time"
+)
+
+// Sync - первый байт каждого кадра.
+const Sync = 0xA5
+
+// MaxPayload - максимальная длина данных кадра.
+const MaxPayload = 16
+
+// Типы кадров.
+const (
+    TypeSetTime = 0x01 // Хост -> устройство: установить время (данные - время)
+    TypeGetTime = 0x02 // Хост -> устройство: запросить время (без данных)
+    TypeTime    = 0x81 // Устройство -> хост: текущее время RTC (данные - время)
+    TypeError   = 0xFF // Устройство -> хост: запрос не выполнен (данные - код ошибки)
+)
+
+// Коды ошибок в кадре TypeError.
+const (
+    ErrCodeUnknownType = 0x01 // Неизвестный тип кадра
+    ErrCodeBadPayload  = 0x02 // Неверная длина данных
+    ErrCodeRejected    = 0x03 // Часы отклонили новое время (например, перевод назад)
+)
+
+// timeSize - длина закодированного времени.
+const timeSize = 12
+
+var (
+    // ErrPayloadTooLong возвращается при попытке закодировать слишком длинные данные.
+    ErrPayloadTooLong = errors.New("provision: payload too long")
+    // ErrBadTime возвращается, если данные кадра не являются закодированным временем.
+    ErrBadTime = errors.New("provision: malformed time payload")
+)
+
+// Frame - декодированный кадр протокола.
+type Frame struct {
+    Type    uint8
+    Payload []byte
+}
+
+// AppendFrame добавляет к dst закодированный кадр.
+func AppendFrame(dst []byte, f Frame) ([]byte, error) {
+    if len(f.Payload) > MaxPayload {
+        return dst, ErrPayloadTooLong
+    }
+    start := len(dst)
+    dst = append(dst, Sync, f.Type, uint8(len(f.Payload)))
+    dst = append(dst, f.Payload...)
+    crc := crc16(dst[start+1:])
+    return append(dst, uint8(crc>>8), uint8(crc)), nil
+}
+
+// WriteFrame кодирует кадр и записывает его в w.
+func WriteFrame(w io.Writer, f Frame) error {
+    var buf [3 + MaxPayload + 2]byte
+    out, err := AppendFrame(buf[:0], f)
+    if err != nil {
+        return err
+    }
+    _, err = w.Write(out)
+    return err
+}
+
+// AppendTime добавляет к dst закодированное время.
+func AppendTime(dst []byte, t time.Time) []byte {
+    dst = binary.BigEndian.AppendUint64(dst, uint64(t.Unix()))
+    return binary.BigEndian.AppendUint32(dst, uint32(t.Nanosecond()))
+}
+
+// ParseTime декодирует время из данных кадра. Результат в UTC.
+func ParseTime(p []byte) (time.Time, error) {
+    if len(p) != timeSize {
+        return time.Time{}, ErrBadTime
+    }
+    sec := int64(binary.BigEndian.Uint64(p))
+    nsec := int64(binary.BigEndian.Uint32(p[8:]))
+    if nsec >= int64(time.Second) {
+        return time.Time{}, ErrBadTime
+    }
+    return time.Unix(sec, nsec).UTC(), nil
+}
+
+// Decoder собирает кадры из потока байт по одному байту,
+// что удобно при неблокирующем чтении UART на микроконтроллере.
+// Кадры с неверной CRC молча отбрасываются, после чего декодер
+// ищет начало следующего кадра среди уже принятых байт.
+type Decoder struct {
+    buf [2 + MaxPayload + 2]byte // тип, длина, данные, CRC
+    n   int
+    in  bool
+}
+
+// Feed передает декодеру очередной байт. Возвращает кадр и true,
+// когда кадр принят целиком. Данные кадра действительны до следующего вызова Feed.
+func (d *Decoder) Feed(b byte) (Frame, bool) {
+    if !d.in {
+        if b == Sync {
+            d.in = true
+            d.n = 0
+        }
+        return Frame{}, false
+    }
+
+    d.buf[d.n] = b
+    d.n++
+    if d.n == 2 && d.buf[1] > MaxPayload {
+        d.resync()
+        return Frame{}, false
+    }
+    if d.n < 2 || d.n < 2+int(d.buf[1])+2 {
+        return Frame{}, false
+    }
+
+    end := 2 + int(d.buf[1])
+    crc := uint16(d.buf[end])<<8 | uint16(d.buf[end+1])
+    if crc16(d.buf[:end]) != crc {
+        d.resync()
+        return Frame{}, false
+    }
+    d.in = false
+    return Frame{Type: d.buf[0], Payload: d.buf[2:end]}, true
+}
+
+// resync отбрасывает ошибочный кадр и повторно разбирает принятые байты:
+// байт 0xA5 внутри них мог быть началом настоящего кадра.
+func (d *Decoder) resync() {
+    var rest [len(d.buf)]byte
+    n := copy(rest[:], d.buf[:d.n])
+    d.in = false
+    for _, b := range rest[:n] {
+        d.Feed(b)
+    }
+}
+
+// Clock - часы, которыми управляет Handler. *ds1302.DS1302 удовлетворяет интерфейсу.
+type Clock interface {
+    SetTime(t time.Time) error
+    ReadTime() time.Time
+}
+
+// Handler обрабатывает кадры протокола на стороне прошивки.
+type Handler struct {
+    Clock Clock
+    dec   Decoder
+}
+
+// Feed передает обработчику очередной байт из UART. Когда кадр принят,
+// обработчик выполняет запрос и записывает ответ в w.
+func (h *Handler) Feed(b byte, w io.Writer) error {
+    f, ok := h.dec.Feed(b)
+    if !ok {
+        return nil
+    }
+    return h.handle(f, w)
+}
+
+func (h *Handler) handle(f Frame, w io.Writer) error {
+    switch f.Type {
+    case TypeSetTime:
+        t, err := ParseTime(f.Payload)
+        if err != nil {
+            return WriteFrame(w, Frame{Type: TypeError, Payload: []byte{ErrCodeBadPayload}})
+        }
+        if err := h.Clock.SetTime(t); err != nil {
+            return WriteFrame(w, Frame{Type: TypeError, Payload: []byte{ErrCodeRejected}})
+        }
+    case TypeGetTime:
+    default:
+        return WriteFrame(w, Frame{Type: TypeError, Payload: []byte{ErrCodeUnknownType}})
+    }
+
+    var buf [timeSize]byte
+    return WriteFrame(w, Frame{Type: TypeTime, Payload: AppendTime(buf[:0], h.Clock.ReadTime())})
+}
+
+// crc16 вычисляет CRC-16/CCITT-FALSE (полином 0x1021, начальное значение 0xFFFF).
+func crc16(data []byte) uint16 {
+    crc := uint16(0xFFFF)
+    for _, b := range data {
+        crc ^= uint16(b) << 8
+        for i := 0; i < 8; i++ {
+            if crc&0x8000 != 0 {
+                crc = crc<<1 ^ 0x1021
+            } else {
+                crc <<= 1
+            }
+        }
+    }
+    return crc
+}
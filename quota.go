@@ -0,0 +1,110 @@
+package ds1302
+
+import (
+    "encoding/binary"
+    "time"
+)
+
+// QuotaSize - число байт ОЗУ, занимаемых Quota: начало окна (4 байта),
+// счетчик и проверочный байт.
+const QuotaSize = 6
+
+// quotaMagic маскирует проверочный байт, чтобы пустое ОЗУ не выглядело
+// как корректное состояние.
+const quotaMagic = 0x3C
+
+// Quota ограничивает число событий за период, например "не больше 5 SMS
+// в сутки". Состояние хранится в резервном ОЗУ, а время берется из DS1302,
+// поэтому лимит соблюдается и после перезагрузок МК.
+//
+// Окна выравниваются по границам period от нулевого времени Go:
+// для суток это полночь UTC, для часа - начало часа.
+type Quota struct {
+    dev    *DS1302
+    addr   uint8
+    limit  uint8
+    period time.Duration
+}
+
+// NewQuota создает квоту в ОЗУ по адресу addr: не больше limit событий
+// за period.
+//
+//     sms := ds1302.NewQuota(rtc, 8, 5, 24*time.Hour)
+//     if ok, _ := sms.Allow(); ok {
+//         sendAlert()
+//     }
+func NewQuota(d *DS1302, addr uint8, limit uint8, period time.Duration) *Quota {
+    return &Quota{dev: d, addr: addr, limit: limit, period: period}
+}
+
+// Allow расходует одно событие квоты. Возвращает false, если лимит
+// текущего окна исчерпан.
+func (q *Quota) Allow() (bool, error) {
+    q.dev.mu.Lock()
+    defer q.dev.mu.Unlock()
+    window, used, err := q.state()
+    if err != nil {
+        return false, err
+    }
+    if used >= q.limit {
+        return false, nil
+    }
+    return true, q.store(window, used+1)
+}
+
+// Remaining возвращает число событий, оставшихся в текущем окне.
+func (q *Quota) Remaining() (int, error) {
+    q.dev.mu.Lock()
+    defer q.dev.mu.Unlock()
+    _, used, err := q.state()
+    if err != nil {
+        return 0, err
+    }
+    if used >= q.limit {
+        return 0, nil
+    }
+    return int(q.limit - used), nil
+}
+
+// Reset возвращает квоте полный лимит.
+func (q *Quota) Reset() error {
+    return q.dev.WriteRAMBytes(q.addr, make([]byte, QuotaSize))
+}
+
+// state возвращает начало текущего окна и число израсходованных в нем событий.
+// Испорченное состояние или состояние другого окна (в том числе после
+// перевода часов) считается пустым.
+func (q *Quota) state() (uint32, uint8, error) {
+    now, err := q.dev.readTime()
+    if err != nil {
+        return 0, 0, err
+    }
+    window := Pack(now.Truncate(q.period))
+
+    var buf [QuotaSize]byte
+    if err := q.dev.readRAMBytes(q.addr, buf[:]); err != nil {
+        return 0, 0, err
+    }
+    if buf[5] != quotaCheck(buf[:5]) || binary.LittleEndian.Uint32(buf[:4]) != window {
+        return window, 0, nil
+    }
+    return window, buf[4], nil
+}
+
+// store записывает состояние квоты.
+func (q *Quota) store(window uint32, used uint8) error {
+    var buf [QuotaSize]byte
+    binary.LittleEndian.PutUint32(buf[:4], window)
+    buf[4] = used
+    buf[5] = quotaCheck(buf[:5])
+    return q.dev.writeRAMBytes(q.addr, buf[:])
+}
+
+// quotaCheck вычисляет проверочный байт состояния.
+func quotaCheck(b []byte) byte {
+    c := byte(quotaMagic)
+    for _, v := range b {
+        c ^= v
+    }
+    return c
+}
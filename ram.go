@@ -0,0 +1,163 @@
+package ds1302
+
+import (
+    "errors"
+    "io"
+)
+
+// RAMSize - размер резервного ОЗУ DS1302 в байтах.
+const RAMSize = 31
+
+// ErrRAMOutOfRange возвращается при обращении за пределы резервного ОЗУ.
+var ErrRAMOutOfRange = errors.New("ds1302: RAM address out of range")
+
+// RAMBlockDevice представляет резервное ОЗУ DS1302 как блочное устройство.
+//
+// Набор методов повторяет интерфейс machine.BlockDevice из TinyGo
+// (ReadAt/WriteAt/Size/WriteBlockSize/EraseBlockSize/EraseBlocks),
+// поэтому небольшие слои хранения, написанные для флеш-памяти,
+// могут использовать ОЗУ часов без отдельного кода.
+//
+// Размер блока записи и стирания равен одному байту.
+type RAMBlockDevice struct {
+    dev *DS1302
+}
+
+// RAMBlockDevice возвращает резервное ОЗУ устройства в виде блочного устройства.
+func (d *DS1302) RAMBlockDevice() *RAMBlockDevice {
+    return &RAMBlockDevice{dev: d}
+}
+
+// ReadAt читает len(p) байт начиная со смещения off.
+// При чтении за концом ОЗУ возвращает прочитанное число байт и io.EOF.
+func (r *RAMBlockDevice) ReadAt(p []byte, off int64) (int, error) {
+    if off < 0 {
+        return 0, ErrRAMOutOfRange
+    }
+    if off >= RAMSize {
+        return 0, io.EOF
+    }
+    
+    n := 0
+    for n < len(p) && off+int64(n) < RAMSize {
+        b, err := r.dev.ReadRAM(uint8(off) + uint8(n))
+        if err != nil {
+            return n, err
+        }
+        p[n] = b
+        n++
+    }
+    if n < len(p) {
+        return n, io.EOF
+    }
+    return n, nil
+}
+
+// WriteAt записывает p начиная со смещения off.
+// Запись, не помещающаяся в ОЗУ целиком, не выполняется.
+func (r *RAMBlockDevice) WriteAt(p []byte, off int64) (int, error) {
+    if off < 0 || off+int64(len(p)) > RAMSize {
+        return 0, ErrRAMOutOfRange
+    }
+    if err := r.dev.WriteRAMBytes(uint8(off), p); err != nil {
+        return 0, err
+    }
+    return len(p), nil
+}
+
+// Size возвращает размер ОЗУ в байтах.
+func (r *RAMBlockDevice) Size() int64 {
+    return RAMSize
+}
+
+// WriteBlockSize возвращает размер блока записи (1 байт).
+func (r *RAMBlockDevice) WriteBlockSize() int64 {
+    return 1
+}
+
+// EraseBlockSize возвращает размер блока стирания (1 байт).
+func (r *RAMBlockDevice) EraseBlockSize() int64 {
+    return 1
+}
+
+// EraseBlocks стирает length блоков начиная с блока start.
+// Как и у флеш-памяти, стертые байты принимают значение 0xFF.
+func (r *RAMBlockDevice) EraseBlocks(start, length int64) error {
+    if start < 0 || length < 0 || start+length > RAMSize {
+        return ErrRAMOutOfRange
+    }
+    
+    erased := make([]byte, length)
+    for i := range erased {
+        erased[i] = 0xFF
+    }
+    return r.dev.WriteRAMBytes(uint8(start), erased)
+}
+
+// RAMStream представляет резервное ОЗУ DS1302 как поток с текущей позицией
+// (io.Reader, io.Writer, io.Seeker), чтобы encoding/binary и небольшие
+// сериализаторы писали прямо в ОЗУ часов:
+//
+//     s := rtc.RAMStream()
+//     binary.Write(s, binary.LittleEndian, settings)
+//     s.Seek(0, io.SeekStart)
+//     binary.Read(s, binary.LittleEndian, &settings)
+type RAMStream struct {
+    dev *DS1302
+    pos int64
+}
+
+// RAMStream возвращает поток над резервным ОЗУ с позицией в начале.
+func (d *DS1302) RAMStream() *RAMStream {
+    return &RAMStream{dev: d}
+}
+
+// Read читает до len(p) байт с текущей позиции.
+// В конце ОЗУ возвращает io.EOF.
+func (s *RAMStream) Read(p []byte) (int, error) {
+    if s.pos >= RAMSize {
+        return 0, io.EOF
+    }
+    if rest := RAMSize - s.pos; int64(len(p)) > rest {
+        p = p[:rest]
+    }
+    if err := s.dev.ReadRAMBytes(uint8(s.pos), p); err != nil {
+        return 0, err
+    }
+    s.pos += int64(len(p))
+    return len(p), nil
+}
+
+// Write записывает p с текущей позиции. Запись, не помещающаяся
+// в ОЗУ целиком, не выполняется и возвращает ErrRAMOutOfRange.
+func (s *RAMStream) Write(p []byte) (int, error) {
+    if s.pos+int64(len(p)) > RAMSize {
+        return 0, ErrRAMOutOfRange
+    }
+    if err := s.dev.WriteRAMBytes(uint8(s.pos), p); err != nil {
+        return 0, err
+    }
+    s.pos += int64(len(p))
+    return len(p), nil
+}
+
+// Seek задает позицию для следующего Read или Write.
+// Позиция вне 0..RAMSize возвращает ErrRAMOutOfRange.
+func (s *RAMStream) Seek(offset int64, whence int) (int64, error) {
+    switch whence {
+    case io.SeekStart:
+    case io.SeekCurrent:
+        offset += s.pos
+    case io.SeekEnd:
+        offset += RAMSize
+    default:
+        return s.pos, ErrRAMOutOfRange
+    }
+    if offset < 0 || offset > RAMSize {
+        return s.pos, ErrRAMOutOfRange
+    }
+    s.pos = offset
+    return offset, nil
+}
+
+var _ io.ReadWriteSeeker = (*RAMStream)(nil)
@@ -0,0 +1,130 @@
+package ds1302
+
+import (
+    "context"
+)
+
+// RAMBuffer накапливает записи в резервное ОЗУ в памяти МК и передает их
+// одной пакетной записью по Flush. Подсистемам, которые на каждое событие
+// меняют несколько байт (настройки, журнал), это экономит время на шине
+// и переключения защиты от записи.
+//
+// При первом обращении буфер читает все ОЗУ одной пакетной операцией.
+// Flush записывает пакетом байты с нулевого по последний измененный,
+// поэтому, пока есть несохраненные изменения, остальной код должен писать
+// в ОЗУ через буфер: прямые записи в этот диапазон будут затерты.
+type RAMBuffer struct {
+    dev    *DS1302
+    buf    [RAMSize]byte
+    loaded bool
+    end    int // Конец измененной области; 0 - изменений нет
+}
+
+// NewRAMBuffer создает буфер записей в ОЗУ устройства d.
+func NewRAMBuffer(d *DS1302) *RAMBuffer {
+    return &RAMBuffer{dev: d}
+}
+
+// load читает ОЗУ в буфер при первом обращении.
+func (b *RAMBuffer) load() error {
+    if b.loaded {
+        return nil
+    }
+    if err := b.dev.readBurstContext(context.Background(), RAMBurstRead, b.buf[:]); err != nil {
+        return err
+    }
+    b.loaded = true
+    return nil
+}
+
+// ReadRAM возвращает байт ОЗУ с учетом несохраненных изменений.
+func (b *RAMBuffer) ReadRAM(addr uint8) (uint8, error) {
+    var v [1]byte
+    err := b.ReadRAMBytes(addr, v[:])
+    return v[0], err
+}
+
+// ReadRAMBytes читает len(buf) байт начиная с addr с учетом несохраненных изменений.
+func (b *RAMBuffer) ReadRAMBytes(addr uint8, buf []byte) error {
+    b.dev.mu.Lock()
+    defer b.dev.mu.Unlock()
+    if int(addr)+len(buf) > RAMSize {
+        return b.dev.fail(ErrRAMOutOfRange)
+    }
+    if err := b.load(); err != nil {
+        return err
+    }
+    copy(buf, b.buf[addr:])
+    return nil
+}
+
+// WriteRAM изменяет байт в буфере.
+func (b *RAMBuffer) WriteRAM(addr, value uint8) error {
+    return b.WriteRAMBytes(addr, []byte{value})
+}
+
+// WriteRAMBytes изменяет байты буфера начиная с addr. Шина не используется,
+// кроме первого обращения; совпадающие с буфером байты не считаются изменением.
+func (b *RAMBuffer) WriteRAMBytes(addr uint8, data []byte) error {
+    b.dev.mu.Lock()
+    defer b.dev.mu.Unlock()
+    if int(addr)+len(data) > RAMSize {
+        return b.dev.fail(ErrRAMOutOfRange)
+    }
+    if err := b.load(); err != nil {
+        return err
+    }
+    for i, v := range data {
+        at := int(addr) + i
+        if b.buf[at] != v {
+            b.buf[at] = v
+            if at+1 > b.end {
+                b.end = at + 1
+            }
+        }
+    }
+    return nil
+}
+
+// Dirty сообщает, есть ли несохраненные изменения.
+func (b *RAMBuffer) Dirty() bool {
+    b.dev.mu.Lock()
+    defer b.dev.mu.Unlock()
+    return b.end > 0
+}
+
+// Flush записывает изменения в ОЗУ одной пакетной операцией, снимая
+// защиту от записи один раз. Без изменений шина не используется.
+func (b *RAMBuffer) Flush() error {
+    b.dev.mu.Lock()
+    defer b.dev.mu.Unlock()
+    if b.end == 0 {
+        return nil
+    }
+    d := b.dev
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    err := d.writeBurstContext(context.Background(), RAMBurstWrite, b.buf[:b.end])
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    if err != nil {
+        return err
+    }
+    if d.ramUsage != nil {
+        for i := 0; i < b.end; i++ {
+            d.ramUsage[i]++
+        }
+    }
+    b.end = 0
+    return nil
+}
+
+// Discard отбрасывает несохраненные изменения; следующее обращение
+// заново прочитает ОЗУ.
+func (b *RAMBuffer) Discard() {
+    b.dev.mu.Lock()
+    defer b.dev.mu.Unlock()
+    b.loaded = false
+    b.end = 0
+}
@@ -0,0 +1,86 @@
+package ds1302
+
+import (
+    "strconv"
+)
+
+// RAMFault - ошибка TestRAM: байты ОЗУ, не сохранившие тестовые шаблоны.
+type RAMFault struct {
+    Cells uint32 // Бит n установлен, если неисправен байт ОЗУ n
+}
+
+// Failed сообщает, неисправен ли байт addr.
+func (e *RAMFault) Failed(addr uint8) bool {
+    return addr < RAMSize && e.Cells&(1<<addr) != 0
+}
+
+// Error перечисляет неисправные байты: "ds1302: RAM test failed at bytes 3, 17".
+func (e *RAMFault) Error() string {
+    s := "ds1302: RAM test failed at bytes "
+    first := true
+    for addr := uint8(0); addr < RAMSize; addr++ {
+        if !e.Failed(addr) {
+            continue
+        }
+        if !first {
+            s += ", "
+        }
+        s += strconv.Itoa(int(addr))
+        first = false
+    }
+    return s
+}
+
+// TestRAM проверяет все 31 байт резервного ОЗУ шаблонами 0x55, 0xAA,
+// адресом байта и его инверсией и возвращает *RAMFault со списком
+// неисправных байтов. Шаблон записывается во все байты до чтения,
+// поэтому замыкания адресных линий тоже обнаруживаются. Исходное
+// содержимое ОЗУ сохраняется и восстанавливается. Занимает десятки
+// миллисекунд; подходит для входного контроля клонов DS1302.
+func (d *DS1302) TestRAM() error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+
+    fault := RAMFault{Cells: d.testRAM()}
+    if fault.Cells != 0 {
+        return d.fail(&fault)
+    }
+    return nil
+}
+
+// testRAM выполняет проверку TestRAM и возвращает маску неисправных байтов.
+func (d *DS1302) testRAM() uint32 {
+    var saved, pattern [RAMSize]byte
+    for i := range saved {
+        saved[i] = d.readRegister(DS1302_RAM_READ + uint8(i)*2)
+    }
+
+    var cells uint32
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    for pass := 0; pass < 4; pass++ {
+        for i := range pattern {
+            switch pass {
+            case 0:
+                pattern[i] = 0x55
+            case 1:
+                pattern[i] = 0xAA
+            case 2:
+                pattern[i] = uint8(i)
+            case 3:
+                pattern[i] = ^uint8(i)
+            }
+        }
+        d.writeRAM(0, pattern[:])
+        for i, want := range pattern {
+            if d.readRegister(DS1302_RAM_READ+uint8(i)*2) != want {
+                cells |= 1 << i
+            }
+        }
+    }
+    d.writeRAM(0, saved[:])
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    return cells
+}
@@ -0,0 +1,68 @@
+package ds1302
+
+// RAMUsage - число записей в каждый байт резервного ОЗУ.
+type RAMUsage [RAMSize]uint32
+
+// Total возвращает общее число записей в ОЗУ.
+func (u *RAMUsage) Total() uint32 {
+    var total uint32
+    for _, n := range u {
+        total += n
+    }
+    return total
+}
+
+// Max возвращает адрес самого часто записываемого байта и число записей в него.
+func (u *RAMUsage) Max() (addr uint8, count uint32) {
+    for i, n := range u {
+        if n > count {
+            addr, count = uint8(i), n
+        }
+    }
+    return addr, count
+}
+
+// TrackRAMWrites включает или выключает учет записей в каждый байт ОЗУ.
+// Учет ведется в памяти микроконтроллера и сбрасывается при выключении
+// и перезагрузке. Позволяет подсистемам распределять записи по ОЗУ,
+// а пользователю - заметить цикл, непрерывно перезаписывающий одну ячейку.
+func (d *DS1302) TrackRAMWrites(enable bool) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if !enable {
+        d.ramUsage = nil
+        return
+    }
+    if d.ramUsage == nil {
+        d.ramUsage = new(RAMUsage)
+    }
+}
+
+// RAMUsage возвращает копию счетчиков записей в ОЗУ.
+// Если учет выключен, все счетчики равны нулю.
+func (d *DS1302) RAMUsage() RAMUsage {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if d.ramUsage == nil {
+        return RAMUsage{}
+    }
+    return *d.ramUsage
+}
+
+// ResetRAMUsage обнуляет счетчики записей в ОЗУ.
+func (d *DS1302) ResetRAMUsage() {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if d.ramUsage != nil {
+        *d.ramUsage = RAMUsage{}
+    }
+}
+
+// countRAMWrite учитывает запись по команде cmd, если она адресует байт ОЗУ.
+func (d *DS1302) countRAMWrite(cmd uint8) {
+    c := Command(cmd)
+    if d.ramUsage == nil || !c.IsRAM() || c.IsRead() || c.IsBurst() {
+        return
+    }
+    d.ramUsage[c.Index()]++
+}
@@ -0,0 +1,45 @@
+package ds1302
+
+import (
+    "context"
+)
+
+// RawTime - регистры часов DS1302 в том виде, в котором их вернула
+// микросхема: BCD вместе с битами флагов. Флаги дополнительно разобраны
+// в отдельные поля.
+type RawTime struct {
+    Seconds uint8 // Секунды (BCD) и бит CH
+    Minutes uint8 // Минуты (BCD)
+    Hours   uint8 // Часы (BCD) и биты 12/24, AM/PM
+    Date    uint8 // День месяца (BCD)
+    Month   uint8 // Месяц (BCD)
+    Weekday uint8 // День недели (1-7)
+    Year    uint8 // Год (BCD, 00-99)
+    WP      uint8 // Регистр защиты от записи
+
+    Halted         bool // Бит CH: генератор остановлен
+    Hour12         bool // Часы хранятся в 12-часовом формате
+    PM             bool // Бит PM (только в 12-часовом формате)
+    WriteProtected bool // Бит WP
+}
+
+// ReadRawTime читает регистры часов одной пакетной операцией без
+// декодирования - для мостов к протоколам и индикаторам, работающим
+// с BCD, и для разбора расхождений с другими библиотеками.
+func (d *DS1302) ReadRawTime() (RawTime, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    var b [8]uint8
+    if err := d.readBurstContext(context.Background(), ClockBurstRead, b[:]); err != nil {
+        return RawTime{}, err
+    }
+    return RawTime{
+        Seconds: b[0], Minutes: b[1], Hours: b[2], Date: b[3],
+        Month: b[4], Weekday: b[5], Year: b[6], WP: b[7],
+
+        Halted:         b[0]&DS1302_CH_BIT != 0,
+        Hour12:         b[2]&DS1302_12H_BIT != 0,
+        PM:             b[2]&DS1302_12H_BIT != 0 && b[2]&DS1302_PM_BIT != 0,
+        WriteProtected: b[7]&DS1302_WP_BIT != 0,
+    }, nil
+}
@@ -0,0 +1,85 @@
+package ds1302
+
+import (
+    "errors"
+    "time"
+)
+
+// ErrNoValidClock возвращается Redundant.ReadTime, если ни один модуль
+// не показывает правдоподобное время.
+var ErrNoValidClock = errors.New("ds1302: no redundant clock has valid time")
+
+// DefaultMaxSkew - допустимое расхождение модулей Redundant по умолчанию.
+const DefaultMaxSkew = 2 * time.Second
+
+// Redundant объединяет два модуля DS1302, например на общих CLK и DAT
+// с раздельными RST, для автономных регистраторов: время читается
+// с основного модуля, пока он исправен, а Check сообщает об отказе
+// или расхождении модулей.
+//
+// Модули на общих линиях нельзя опрашивать из разных горутин одновременно:
+// блокировка у каждого DS1302 своя.
+type Redundant struct {
+    Primary   *DS1302
+    Secondary *DS1302
+    MaxSkew   time.Duration // Допустимое расхождение (0 - DefaultMaxSkew)
+}
+
+// RedundantStatus - результат Redundant.Check.
+type RedundantStatus struct {
+    Primary   ValidityReason // ReasonOK, если основной модуль исправен
+    Secondary ValidityReason // ReasonOK, если резервный модуль исправен
+    Skew      time.Duration  // Время резервного минус время основного (если оба исправны)
+    Drifting  bool           // Модули разошлись больше чем на MaxSkew
+}
+
+// OK сообщает, что оба модуля исправны и согласованы.
+func (s RedundantStatus) OK() bool {
+    return s.Primary == ReasonOK && s.Secondary == ReasonOK && !s.Drifting
+}
+
+// NewRedundant создает пару из основного и резервного модулей.
+func NewRedundant(primary, secondary *DS1302) *Redundant {
+    return &Redundant{Primary: primary, Secondary: secondary}
+}
+
+// Check проверяет оба модуля через IsTimeValid и сравнивает их время.
+// Расхождение не говорит, какой из модулей ушел: для этого нужен
+// третий источник (например, Syncer).
+func (r *Redundant) Check() RedundantStatus {
+    var s RedundantStatus
+    _, s.Primary = r.Primary.IsTimeValid()
+    _, s.Secondary = r.Secondary.IsTimeValid()
+    if s.Primary != ReasonOK || s.Secondary != ReasonOK {
+        return s
+    }
+    s.Skew = r.Secondary.ReadTime().Sub(r.Primary.ReadTime())
+    maxSkew := r.MaxSkew
+    if maxSkew == 0 {
+        maxSkew = DefaultMaxSkew
+    }
+    s.Drifting = s.Skew > maxSkew || s.Skew < -maxSkew
+    return s
+}
+
+// ReadTime читает время основного модуля, а если оно неправдоподобно -
+// резервного.
+func (r *Redundant) ReadTime() (time.Time, error) {
+    if ok, _ := r.Primary.IsTimeValid(); ok {
+        return r.Primary.ReadTime(), nil
+    }
+    if ok, _ := r.Secondary.IsTimeValid(); ok {
+        return r.Secondary.ReadTime(), nil
+    }
+    return time.Time{}, ErrNoValidClock
+}
+
+// SetTime устанавливает время на обоих модулях. Ошибка одного модуля
+// не мешает установке другого; возвращается первая ошибка.
+func (r *Redundant) SetTime(t time.Time) error {
+    err := r.Primary.SetTime(t)
+    if err2 := r.Secondary.SetTime(t); err == nil {
+        err = err2
+    }
+    return err
+}
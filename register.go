@@ -0,0 +1,160 @@
+package ds1302
+
+import (
+    "errors"
+    "strconv"
+)
+
+var (
+    // ErrInvalidRegister возвращается для адреса, не соответствующего
+    // ни одному регистру часов или байту ОЗУ DS1302.
+    ErrInvalidRegister = errors.New("ds1302: invalid register address")
+    // ErrRegisterDirection возвращается, если адрес чтения используется
+    // для записи или наоборот.
+    ErrRegisterDirection = errors.New("ds1302: register address has wrong read/write bit")
+)
+
+// Command - адресный (командный) байт DS1302, с которого начинается каждый обмен.
+//
+//     бит 7    всегда 1
+//     бит 6    1 - ОЗУ, 0 - часы
+//     биты 5-1 номер регистра (31 - пакетный обмен)
+//     бит 0    1 - чтение, 0 - запись
+//
+// Команды следует получать конструкторами ClockRead/ClockWrite/RAMRead/RAMWrite
+// и константами пакетных команд вместо сырых констант DS1302_*.
+type Command uint8
+
+// Поля адресного байта DS1302.
+const (
+    addrCommandBit = 0x80 // Бит 7 всегда равен 1
+    addrRAMBit     = 0x40 // Бит 6: 1 - ОЗУ, 0 - часы
+    addrReadBit    = 0x01 // Бит 0: 1 - чтение, 0 - запись
+    addrIndexMask  = 0x3E // Биты 5-1: номер регистра
+    addrBurstIndex = 31   // Номер регистра пакетной команды
+)
+
+// ClockRegister - номер регистра часов. Типизированные константы
+// не позволяют передать в ClockRead/ClockWrite несуществующий регистр.
+type ClockRegister uint8
+
+const (
+    RegSeconds      ClockRegister = iota // Секунды и бит CH
+    RegMinutes                           // Минуты
+    RegHours                             // Часы и формат 12/24
+    RegDate                              // День месяца
+    RegMonth                             // Месяц
+    RegWeekday                           // День недели
+    RegYear                              // Год
+    RegWriteProtect                      // Защита от записи
+    RegTrickle                           // Подзарядка (trickle charger)
+    
+    clockRegisters = iota // Число одиночных регистров часов
+)
+
+// Пакетные команды.
+const (
+    ClockBurstRead  Command = addrCommandBit | addrBurstIndex<<1 | addrReadBit
+    ClockBurstWrite Command = addrCommandBit | addrBurstIndex<<1
+    RAMBurstRead    Command = addrCommandBit | addrRAMBit | addrBurstIndex<<1 | addrReadBit
+    RAMBurstWrite   Command = addrCommandBit | addrRAMBit | addrBurstIndex<<1
+)
+
+// ClockRead возвращает команду чтения регистра часов r.
+func ClockRead(r ClockRegister) Command {
+    return Command(addrCommandBit | uint8(r)<<1 | addrReadBit)
+}
+
+// ClockWrite возвращает команду записи регистра часов r.
+func ClockWrite(r ClockRegister) Command {
+    return Command(addrCommandBit | uint8(r)<<1)
+}
+
+// RAMRead возвращает команду чтения байта ОЗУ с номером index (0-30).
+// Номер вне диапазона дает недопустимую команду (см. invalidCommand),
+// которую отвергнут методы драйвера, а не пакетную команду или другой байт.
+func RAMRead(index uint8) Command {
+    if index >= RAMSize {
+        return invalidCommand
+    }
+    return Command(addrCommandBit | addrRAMBit | index<<1 | addrReadBit)
+}
+
+// RAMWrite возвращает команду записи байта ОЗУ с номером index (0-30).
+// Номер вне диапазона дает недопустимую команду, как и у RAMRead.
+func RAMWrite(index uint8) Command {
+    if index >= RAMSize {
+        return invalidCommand
+    }
+    return Command(addrCommandBit | addrRAMBit | index<<1)
+}
+
+// invalidCommand - команда без обязательного бита 7: Valid ее отвергает.
+const invalidCommand Command = 0
+
+// IsRead сообщает, является ли команда командой чтения.
+func (c Command) IsRead() bool { return c&addrReadBit != 0 }
+
+// IsRAM сообщает, адресует ли команда ОЗУ.
+func (c Command) IsRAM() bool { return c&addrRAMBit != 0 }
+
+// IsBurst сообщает, является ли команда пакетной.
+func (c Command) IsBurst() bool { return c.Index() == addrBurstIndex }
+
+// Index возвращает номер регистра или байта ОЗУ.
+func (c Command) Index() uint8 { return uint8(c&addrIndexMask) >> 1 }
+
+// Valid проверяет, что команда адресует одиночный регистр часов
+// или байт ОЗУ. Пакетные команды считаются недопустимыми.
+func (c Command) Valid() error {
+    if c&addrCommandBit == 0 || c.IsBurst() {
+        return ErrInvalidRegister
+    }
+    if c.IsRAM() {
+        if c.Index() >= RAMSize {
+            return ErrInvalidRegister
+        }
+        return nil
+    }
+    if c.Index() >= clockRegisters {
+        return ErrInvalidRegister
+    }
+    return nil
+}
+
+// validate проверяет команду одиночного обмена с нужным направлением.
+func (c Command) validate(read bool) error {
+    if err := c.Valid(); err != nil {
+        return err
+    }
+    if c.IsRead() != read {
+        return ErrRegisterDirection
+    }
+    return nil
+}
+
+// clockRegisterNames - имена регистров часов для String.
+var clockRegisterNames = [clockRegisters]string{
+    "seconds", "minutes", "hours", "date", "month", "weekday", "year", "wp", "trickle",
+}
+
+// String возвращает описание команды, например "clock seconds read" или "ram 5 write".
+func (c Command) String() string {
+    dir := " write"
+    if c.IsRead() {
+        dir = " read"
+    }
+    switch {
+    case c&addrCommandBit == 0:
+        return "invalid"
+    case c.IsBurst() && c.IsRAM():
+        return "ram burst" + dir
+    case c.IsBurst():
+        return "clock burst" + dir
+    case c.IsRAM() && c.Index() < RAMSize:
+        return "ram " + strconv.Itoa(int(c.Index())) + dir
+    case !c.IsRAM() && c.Index() < clockRegisters:
+        return "clock " + clockRegisterNames[c.Index()] + dir
+    }
+    return "invalid"
+}
@@ -0,0 +1,22 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// RTC - основная поверхность драйвера: настройка, время, состояние и ОЗУ.
+// Прикладному коду и тестам удобнее зависеть от RTC, а не от *DS1302:
+// интерфейсу удовлетворяют *DS1302 (в том числе поверх модели микросхемы
+// из пакета sim) и заглушка sim.VirtualClock.
+type RTC interface {
+    Configure(cfg Config)
+    SetTime(t time.Time) error
+    ReadTime() time.Time
+    Status() (Status, error)
+    ReadRAM(addr uint8) (uint8, error)
+    WriteRAM(addr, value uint8) error
+    ReadRAMBytes(addr uint8, buf []byte) error
+    WriteRAMBytes(addr uint8, data []byte) error
+}
+
+var _ RTC = (*DS1302)(nil)
@@ -0,0 +1,83 @@
+package ds1302
+
+import (
+    "encoding/binary"
+    "math"
+    "time"
+)
+
+// RuntimeCounterSize - число байт ОЗУ, занимаемых RuntimeCounter.
+const RuntimeCounterSize = 4
+
+// RuntimeCounter - счетчик наработки оборудования ("станок отработал
+// 1243 часа") в резервном ОЗУ DS1302. Наработка хранится в целых секундах
+// (4 байта, little-endian); доли секунды переносятся между вызовами
+// AddRuntime в памяти МК. Счетчик насыщается на 2^32-1 секунд
+// (около 136 лет) вместо переполнения.
+type RuntimeCounter struct {
+    dev  *DS1302
+    addr uint8
+    frac time.Duration // Не записанный в ОЗУ остаток меньше секунды
+}
+
+// NewRuntimeCounter создает счетчик наработки в ОЗУ по адресу addr.
+func NewRuntimeCounter(d *DS1302, addr uint8) *RuntimeCounter {
+    return &RuntimeCounter{dev: d, addr: addr}
+}
+
+// AddRuntime добавляет к наработке интервал d. Отрицательные интервалы
+// игнорируются.
+func (c *RuntimeCounter) AddRuntime(d time.Duration) error {
+    if d <= 0 {
+        return nil
+    }
+    c.dev.mu.Lock()
+    defer c.dev.mu.Unlock()
+    c.frac += d
+    secs := c.frac / time.Second
+    if secs == 0 {
+        return nil
+    }
+    
+    total, err := c.seconds()
+    if err != nil {
+        return err
+    }
+    if uint64(secs) > math.MaxUint32-uint64(total) {
+        total = math.MaxUint32
+    } else {
+        total += uint32(secs)
+    }
+    c.frac -= secs * time.Second
+    
+    var buf [RuntimeCounterSize]byte
+    binary.LittleEndian.PutUint32(buf[:], total)
+    return c.dev.writeRAMBytes(c.addr, buf[:])
+}
+
+// TotalRuntime возвращает накопленную наработку.
+func (c *RuntimeCounter) TotalRuntime() (time.Duration, error) {
+    c.dev.mu.Lock()
+    defer c.dev.mu.Unlock()
+    total, err := c.seconds()
+    if err != nil {
+        return 0, err
+    }
+    return time.Duration(total)*time.Second + c.frac, nil
+}
+
+// Reset обнуляет наработку.
+func (c *RuntimeCounter) Reset() error {
+    c.dev.mu.Lock()
+    defer c.dev.mu.Unlock()
+    c.frac = 0
+    return c.dev.writeRAMBytes(c.addr, make([]byte, RuntimeCounterSize))
+}
+
+func (c *RuntimeCounter) seconds() (uint32, error) {
+    var buf [RuntimeCounterSize]byte
+    if err := c.dev.readRAMBytes(c.addr, buf[:]); err != nil {
+        return 0, err
+    }
+    return binary.LittleEndian.Uint32(buf[:]), nil
+}
@@ -0,0 +1,41 @@
+package ds1302
+
+import (
+    "errors"
+)
+
+// ErrUnstableRead учитывается в Metrics, если при Config.DoubleSample
+// прочитанный байт не удалось получить без расхождений выборок
+// за sampleRetries повторов. Драйвер возвращает значение последней попытки;
+// пакетное чтение в последней попытке дочитывается целиком, поэтому все
+// байты относятся к одному снимку регистров.
+var ErrUnstableRead = errors.New("ds1302: data line unstable during read")
+
+// sampleRetries - число повторов чтения при расхождении выборок.
+const sampleRetries = 3
+
+// sampleBit читает бит с линии DAT. При Config.DoubleSample линия читается
+// второй раз спустя паузу, пока CLK еще в высоком уровне и микросхема
+// держит тот же бит; stable сообщает, совпали ли выборки.
+func (d *DS1302) sampleBit() (bit, stable bool) {
+    bit = d.dat.Get()
+    if !d.cfg.DoubleSample {
+        return bit, true
+    }
+    d.delay(edgeDelay)
+    return bit, d.dat.Get() == bit
+}
+
+// retryRead решает, повторять ли чтение после попытки attempt (с нуля).
+// Повторы учитываются в Metrics.Retries, исчерпанные - как ErrUnstableRead.
+func (d *DS1302) retryRead(attempt int, stable bool) bool {
+    if stable {
+        return false
+    }
+    if attempt < sampleRetries {
+        d.metrics.Retries++
+        return true
+    }
+    d.fail(ErrUnstableRead)
+    return false
+}
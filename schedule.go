@@ -0,0 +1,274 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// Trigger - момент срабатывания задания в течение суток: фиксированное
+// время (Daily) или солнечное событие со сдвигом (At). Дни, в которые
+// задание срабатывает, ограничивают Days, MonthDay и Week (см. On,
+// OnMonthDay, OnWeek); нулевые значения - каждый день.
+type Trigger struct {
+    Solar  SolarEvent    // Солнечное событие или 0 для фиксированного времени
+    Hour   uint8         // Час фиксированного времени (0-23)
+    Minute uint8         // Минута фиксированного времени (0-59)
+    Offset time.Duration // Сдвиг относительно момента (например, -30 минут до заката)
+
+    Days     WeekdayMask // Дни недели или 0 - любой день
+    MonthDay int8        // День месяца 1-31, -1 - последний, 0 - любой
+    Week     int8        // Неделя месяца для Days: 1-5, -1 - последняя, 0 - любая
+}
+
+// WeekdayMask - набор дней недели: бит 1<<time.Weekday для каждого дня.
+type WeekdayMask uint8
+
+// Наборы дней недели.
+const (
+    EveryDay WeekdayMask = 0
+    Workdays WeekdayMask = 0x3E // Понедельник-пятница
+    Weekend  WeekdayMask = 0x41 // Суббота и воскресенье
+)
+
+// Days возвращает набор из перечисленных дней недели.
+func Days(days ...time.Weekday) WeekdayMask {
+    var m WeekdayMask
+    for _, wd := range days {
+        m |= 1 << (wd % 7)
+    }
+    return m
+}
+
+// Has сообщает, входит ли wd в набор. Пустой набор содержит все дни.
+func (m WeekdayMask) Has(wd time.Weekday) bool {
+    return m == EveryDay || m&(1<<(wd%7)) != 0
+}
+
+// On ограничивает триггер днями недели days:
+//
+//     ds1302.Daily(6, 45).On(ds1302.Workdays)
+func (t Trigger) On(days WeekdayMask) Trigger {
+    t.Days = days
+    return t
+}
+
+// OnMonthDay ограничивает триггер днем месяца n (1-31, -1 - последний день).
+// В месяцах короче n дней задание не срабатывает.
+func (t Trigger) OnMonthDay(n int8) Trigger {
+    t.MonthDay = n
+    return t
+}
+
+// OnWeek ограничивает триггер n-м днем из Days в месяце (1-5, -1 - последним):
+//
+//     ds1302.Daily(9, 0).On(ds1302.Days(time.Tuesday)).OnWeek(2) // Второй вторник
+func (t Trigger) OnWeek(n int8) Trigger {
+    t.Week = n
+    return t
+}
+
+// matches сообщает, срабатывает ли триггер в календарную дату date.
+func (t Trigger) matches(date time.Time) bool {
+    if !t.Days.Has(date.Weekday()) {
+        return false
+    }
+    y, m, d := date.Date()
+    last := int(daysInMonth(y, uint8(m)))
+    switch {
+    case t.MonthDay > 0 && d != int(t.MonthDay), t.MonthDay < 0 && d != last:
+        return false
+    case t.Week > 0 && (d-1)/7+1 != int(t.Week), t.Week < 0 && d+7 <= last:
+        return false
+    }
+    return true
+}
+
+// horizon возвращает, на сколько дней вперед Next ищет момент срабатывания.
+func (t Trigger) horizon() int {
+    switch {
+    case t.MonthDay != 0 || t.Week != 0:
+        return 400
+    case t.Days != EveryDay:
+        return 8
+    }
+    return 2
+}
+
+// Daily возвращает триггер, срабатывающий каждый день в hour:minute по времени RTC.
+func Daily(hour, minute uint8) Trigger {
+    return Trigger{Hour: hour, Minute: minute}
+}
+
+// At возвращает триггер, срабатывающий каждый день в момент солнечного
+// события ev со сдвигом offset:
+//
+//     sch.Add(ds1302.At(ds1302.Sunset, -15*time.Minute), lightsOn)
+func At(ev SolarEvent, offset time.Duration) Trigger {
+    return Trigger{Solar: ev, Offset: offset}
+}
+
+// Job - задание планировщика.
+type Job struct {
+    Trigger Trigger
+    Fn      func(now time.Time) // Вызывается со временем RTC при срабатывании
+    ID      uint8               // Номер будильника (см. AddAlarm) или 0 для задания Add
+
+    ringing bool      // Будильник сработал и не подтвержден
+    snooze  time.Time // Момент повтора после Snooze
+}
+
+// Ringing сообщает, что будильник сработал и еще не подтвержден
+// (Acknowledge) и не отложен (Snooze).
+func (j *Job) Ringing() bool {
+    return j.ringing
+}
+
+// SnoozedUntil возвращает момент повтора отложенного будильника
+// или нулевое время, если он не отложен.
+func (j *Job) SnoozedUntil() time.Time {
+    return j.snooze
+}
+
+// Scheduler вызывает задания по часам DS1302 - фиксированное время суток
+// или восход и заход Солнца для контроллеров освещения и полива.
+//
+// Время RTC считается UTC (см. EnableLocalOffset); фиксированное время
+// заданий сравнивается с показаниями часов как есть.
+type Scheduler struct {
+    RTC       *DS1302
+    Latitude  float64 // Широта в градусах (север положительный), для солнечных событий
+    Longitude float64 // Долгота в градусах (восток положительный)
+
+    // OnAlarm вызывается при срабатывании будильника с его номером
+    // (см. AddAlarm и LoadAlarms).
+    OnAlarm func(id uint8, now time.Time)
+
+    // MaxIdle ограничивает время сна Until и PollSleep (0 - сутки),
+    // например чтобы периодически обновлять индикацию.
+    MaxIdle time.Duration
+
+    jobs      []*Job
+    last      time.Time // Время RTC прошлого опроса
+    alarmAddr uint8     // Область ОЗУ будильников (см. PersistAlarms)
+    alarmSize uint8
+}
+
+// NewScheduler создает планировщик для часов d в точке lat, lon.
+// Координаты нужны только для заданий с солнечными событиями.
+func NewScheduler(d *DS1302, lat, lon float64) *Scheduler {
+    return &Scheduler{RTC: d, Latitude: lat, Longitude: lon}
+}
+
+// Add добавляет задание fn с триггером t.
+func (s *Scheduler) Add(t Trigger, fn func(now time.Time)) *Job {
+    j := &Job{Trigger: t, Fn: fn}
+    s.jobs = append(s.jobs, j)
+    return j
+}
+
+// Remove удаляет задание j.
+func (s *Scheduler) Remove(j *Job) {
+    for i, v := range s.jobs {
+        if v == j {
+            s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+            return
+        }
+    }
+}
+
+// Poll читает часы и вызывает задания, моменты которых наступили
+// после прошлого опроса, и отложенные будильники, чей повтор наступил.
+// Первый вызов только запоминает время; пропущенные за время сна моменты
+// (до суток) выполняются при следующем опросе. Вызывайте Poll из главного
+// цикла хотя бы раз в минуту.
+func (s *Scheduler) Poll() error {
+    now, err := s.RTC.now()
+    if err != nil {
+        return err
+    }
+    if s.last.IsZero() || now.Before(s.last) {
+        // Первый опрос или часы переведены назад: начинаем отсчет заново.
+        s.last = now
+        return nil
+    }
+    
+    prev := s.last
+    s.last = now
+    for _, j := range s.jobs {
+        fire := s.due(j.Trigger, prev, now)
+        if !j.snooze.IsZero() && j.snooze.After(prev) && !j.snooze.After(now) {
+            fire = true
+        }
+        if !fire {
+            continue
+        }
+        if j.ID != 0 {
+            j.ringing, j.snooze = true, time.Time{}
+        }
+        j.Fn(now)
+    }
+    return nil
+}
+
+// Next возвращает ближайший момент срабатывания t после after.
+// ok равно false, если момента нет в ближайшие двое суток (полярный день),
+// а для триггеров с днями недели или месяца - в ближайший год.
+func (s *Scheduler) Next(t Trigger, after time.Time) (time.Time, bool) {
+    for day := -1; day <= t.horizon(); day++ {
+        if occ, ok := s.occurrence(t, after.AddDate(0, 0, day)); ok && occ.After(after) {
+            return occ, true
+        }
+    }
+    return time.Time{}, false
+}
+
+// LastWithin возвращает последний момент срабатывания t в окне
+// (now-d, now], то есть срабатывание, действие которого длительностью d
+// еще идет. Нужна после перезагрузки, чтобы продолжить, например, полив,
+// начатый до нее.
+func (s *Scheduler) LastWithin(t Trigger, now time.Time, d time.Duration) (time.Time, bool) {
+    from := now.Add(-d)
+    // Как и в due, проверяются соседние даты: сдвиг может перенести
+    // момент через полночь.
+    for day := now.AddDate(0, 0, 1); !day.Before(from.AddDate(0, 0, -1)); day = day.AddDate(0, 0, -1) {
+        if occ, ok := s.occurrence(t, day); ok && occ.After(from) && !occ.After(now) {
+            return occ, true
+        }
+    }
+    return time.Time{}, false
+}
+
+// due сообщает, наступил ли момент t в интервале (prev, now].
+func (s *Scheduler) due(t Trigger, prev, now time.Time) bool {
+    // Моменты соседних дат тоже проверяются: сдвиг или солнечное
+    // событие могут перенести их через полночь UTC.
+    for day := -1; day <= 1; day++ {
+        occ, ok := s.occurrence(t, now.AddDate(0, 0, day))
+        if ok && occ.After(prev) && !occ.After(now) {
+            return true
+        }
+    }
+    return false
+}
+
+// occurrence возвращает момент срабатывания t для календарной даты date.
+func (s *Scheduler) occurrence(t Trigger, date time.Time) (time.Time, bool) {
+    if !t.matches(date) {
+        return time.Time{}, false
+    }
+    y, m, d := date.Date()
+    var base time.Time
+    switch t.Solar {
+    case Sunrise, Sunset:
+        rise, set, ok := SunTimes(date, s.Latitude, s.Longitude)
+        if !ok {
+            return time.Time{}, false
+        }
+        base = rise
+        if t.Solar == Sunset {
+            base = set
+        }
+    default:
+        base = time.Date(y, m, d, int(t.Hour), int(t.Minute), 0, 0, time.UTC)
+    }
+    return base.Add(t.Offset), true
+}
@@ -0,0 +1,176 @@
+package ds1302
+
+import (
+    "errors"
+    "strconv"
+    "time"
+
+    "github.com/golangworker/ds1302-driver/bcd"
+)
+
+// ErrSelfTestFailed возвращается SelfTest, если хотя бы одна проверка не пройдена.
+var ErrSelfTestFailed = errors.New("ds1302: self-test failed")
+
+// selfTestWindow - время ожидания смены регистра секунд.
+const selfTestWindow = 2200 * time.Millisecond
+
+// selfTestAddr - байт ОЗУ, используемый для проверки обмена.
+// Исходное содержимое байта восстанавливается после проверки.
+const selfTestAddr = 0
+
+// Report - результат проверки подключения DS1302.
+type Report struct {
+    DATCLKShort      bool     // DAT повторяет уровень CLK: замыкание или перепутанные линии
+    RSTStuck         bool     // Повторные чтения расходятся: RST, вероятно, не переходит в низкий уровень
+    ChipPresent      bool     // Тестовые шаблоны записаны в ОЗУ и прочитаны обратно
+    Halted           bool     // Генератор остановлен (бит CH установлен)
+    SecondsAdvancing bool     // Регистр секунд сменился за время наблюдения
+    Seconds          [2]uint8 // Регистр секунд (BCD) в начале и в конце наблюдения
+}
+
+// OK сообщает, пройдены ли все проверки.
+func (r Report) OK() bool {
+    return !r.DATCLKShort && !r.RSTStuck && r.ChipPresent && !r.Halted && r.SecondsAdvancing
+}
+
+// String возвращает многострочный отчет, удобный для журнала
+// производственной линии или для отправки в поддержку.
+func (r Report) String() string {
+    s := "DS1302 self-test: "
+    if r.OK() {
+        s += "PASS\n"
+    } else {
+        s += "FAIL\n"
+    }
+    s += "  dat/clk short:     " + yesNo(r.DATCLKShort) + "\n"
+    s += "  rst stuck:         " + yesNo(r.RSTStuck) + "\n"
+    s += "  chip present:      " + yesNo(r.ChipPresent) + "\n"
+    s += "  oscillator halted: " + yesNo(r.Halted) + "\n"
+    s += "  seconds advancing: " + yesNo(r.SecondsAdvancing) +
+        " (" + strconv.Itoa(int(bcd.ToDec(r.Seconds[0]))) + " -> " + strconv.Itoa(int(bcd.ToDec(r.Seconds[1]))) + ")\n"
+    return s
+}
+
+func yesNo(v bool) string {
+    if v {
+        return "yes"
+    }
+    return "no"
+}
+
+// SelfTest проверяет подключение микросхемы: замыкание DAT и CLK,
+// залипание RST, наличие микросхемы и ход часов. Проверка хода часов
+// занимает около двух секунд. При непройденной проверке возвращает
+// отчет и ErrSelfTestFailed.
+//
+// Проверки эвристические: линия DAT без подтяжки может случайно
+// повторить уровень CLK, поэтому при DATCLKShort стоит повторить тест.
+func (d *DS1302) SelfTest() (Report, error) {
+    var r Report
+    
+    d.mu.Lock()
+    if err := d.checkOpen(); err != nil {
+        d.mu.Unlock()
+        return r, err
+    }
+    r.DATCLKShort = d.testDATCLKShort()
+    r.ChipPresent, r.RSTStuck = d.testRAMExchange()
+    d.mu.Unlock()
+    
+    if r.ChipPresent {
+        r.Halted, r.SecondsAdvancing, r.Seconds = d.watchSeconds()
+    }
+    
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    // Пока шли проверки, устройство могли закрыть: не открываем его снова.
+    if err := d.checkOpen(); err != nil {
+        return r, err
+    }
+    d.init()
+    if !r.OK() {
+        return r, d.fail(ErrSelfTestFailed)
+    }
+    return r, nil
+}
+
+// testDATCLKShort переключает CLK при невыбранной микросхеме (RST low),
+// когда DAT должна находиться в высокоимпедансном состоянии, и проверяет,
+// не повторяет ли DAT уровень CLK.
+func (d *DS1302) testDATCLKShort() bool {
+    d.lockBus()
+    defer d.unlockBus()
+    d.rst.Low()
+    d.dat.ConfigureInput()
+    
+    follows := 0
+    for i := 0; i < 4; i++ {
+        d.clk.High()
+        d.delay(edgeDelay)
+        high := d.dat.Get()
+        d.clk.Low()
+        d.delay(edgeDelay)
+        low := d.dat.Get()
+        if high && !low {
+            follows++
+        }
+    }
+    return follows == 4
+}
+
+// testRAMExchange записывает в тестовый байт ОЗУ шаблоны 0x55 и 0xAA
+// и читает их обратно дважды подряд. Совпадение обоих шаблонов означает,
+// что микросхема присутствует; расхождение повторных чтений указывает
+// на то, что обмен не завершается по RST.
+func (d *DS1302) testRAMExchange() (present, rstStuck bool) {
+    read := uint8(RAMRead(selfTestAddr))
+    write := uint8(RAMWrite(selfTestAddr))
+    
+    saved := d.readRegister(read)
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    defer func() {
+        d.writeRegister(write, saved)
+        d.writeRegister(DS1302_WP_WRITE, 0x80)
+    }()
+    
+    present = true
+    for _, pattern := range [...]uint8{0x55, 0xAA} {
+        d.writeRegister(write, pattern)
+        first := d.readRegister(read)
+        second := d.readRegister(read)
+        if first != second {
+            rstStuck = true
+        }
+        if first != pattern || second != pattern {
+            present = false
+        }
+    }
+    return present, rstStuck
+}
+
+// watchSeconds наблюдает за регистром секунд до selfTestWindow и сообщает,
+// стоит ли генератор и сменилась ли секунда. Блокировка берется на каждое
+// чтение; если устройство закрыли, наблюдение прекращается.
+func (d *DS1302) watchSeconds() (halted, advancing bool, seconds [2]uint8) {
+    start, err := d.readSeconds()
+    if err != nil {
+        return false, false, seconds
+    }
+    halted = start&DS1302_CH_BIT != 0
+    seconds[0] = start &^ DS1302_CH_BIT
+    seconds[1] = seconds[0]
+    if halted {
+        return halted, false, seconds
+    }
+    deadline := time.Now().Add(selfTestWindow)
+    for time.Now().Before(deadline) && !advancing {
+        time.Sleep(100 * time.Millisecond)
+        sec, err := d.readSeconds()
+        if err != nil {
+            break
+        }
+        seconds[1] = sec &^ DS1302_CH_BIT
+        advancing = seconds[1] != seconds[0]
+    }
+    return halted, advancing, seconds
+}
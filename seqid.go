@@ -0,0 +1,58 @@
+package ds1302
+
+// IDGenerator выдает монотонно возрастающие 64-битные идентификаторы
+// записей: секунды Unix по RTC (старшие 32 бита), младшие 16 бит счетчика
+// запусков и 16-битный порядковый номер в памяти. Идентификаторы
+// уникальны между перезагрузками и позволяют отбрасывать дубликаты
+// записей, выгружаемых устройством с нестабильной связью.
+//
+// Внутри одного запуска идентификаторы строго возрастают, даже если
+// часы переведены назад. Время берется из Timestamp, поэтому генератор
+// не обращается к шине чаще раза в секунду.
+type IDGenerator struct {
+    dev   *DS1302
+    boots *BootCounter
+
+    boot   uint16
+    last   uint64
+    loaded bool
+}
+
+// NewIDGenerator создает генератор на основе часов d и счетчика запусков boots.
+// Счетчик должен увеличиваться один раз при каждом запуске (BootCounter.Increment)
+// до первого вызова Next.
+func NewIDGenerator(d *DS1302, boots *BootCounter) *IDGenerator {
+    return &IDGenerator{dev: d, boots: boots}
+}
+
+// Next возвращает очередной идентификатор.
+func (g *IDGenerator) Next() (uint64, error) {
+    if !g.loaded {
+        n, err := g.boots.Count()
+        if err != nil {
+            return 0, err
+        }
+        g.boot = uint16(n)
+        g.loaded = true
+    }
+    
+    id := uint64(g.dev.Timestamp())<<32 | uint64(g.boot)<<16
+    if id <= g.last {
+        // Та же секунда или часы переведены назад: продолжаем
+        // с последнего идентификатора. Переполненный порядковый номер
+        // переходит в поле секунд, а не в счетчик запусков.
+        secs, _, seq := SplitID(g.last)
+        if seq == 0xFFFF {
+            secs++
+        }
+        id = uint64(secs)<<32 | uint64(g.boot)<<16 | uint64(seq+1)
+    }
+    g.last = id
+    return id, nil
+}
+
+// SplitID разбирает идентификатор IDGenerator на секунды Unix,
+// счетчик запусков и порядковый номер.
+func SplitID(id uint64) (secs uint32, boot, seq uint16) {
+    return uint32(id >> 32), uint16(id >> 16), uint16(id)
+}
@@ -0,0 +1,31 @@
+package ds1302_test
+
+import (
+    "testing"
+    "time"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+)
+
+// TestSetTimeMinuteRollover проверяет, что перенос в микросхеме между
+// записями регистров не откатывает установленное время: при записи секунд
+// первыми перенос 59 -> 00 увеличивал минуты, а следующая запись минут
+// возвращала старое значение, и часы отставали почти на минуту.
+func TestSetTimeMinuteRollover(t *testing.T) {
+    for _, want := range []time.Time{
+        date(2025, 7, 15, 10, 29, 59),
+        date(2025, 7, 15, 23, 59, 59),
+        date(2024, 2, 29, 23, 59, 58),
+    } {
+        rtc, _, clock := newTestRTC(t, ds1302.Hour24)
+        // Каждая транзакция на шине занимает секунду времени микросхемы.
+        clock.step = time.Second
+        if err := rtc.SetTime(want); err != nil {
+            t.Fatalf("SetTime(%v): %v", want, err)
+        }
+        clock.step = 0
+        if got := rtc.ReadTime(); got.Before(want) || got.After(want.Add(2*time.Second)) {
+            t.Errorf("SetTime(%v) on a slow bus: ReadTime = %v", want, got)
+        }
+    }
+}
@@ -0,0 +1,120 @@
+package ds1302
+
+import (
+    "sync"
+)
+
+// ShiftRegister выводит линии RST (CE) многих DS1302 через цепочку
+// сдвиговых регистров 74HC595: три вывода МК (SER, SRCLK, RCLK) дают
+// восемь линий на каждую микросхему цепочки. CLK и DAT у всех DS1302
+// общие - обмен идет только с той, чья линия CE поднята.
+//
+// Так стенд прошивки модулей программирует десятки RTC одним МК:
+//
+//     sr := ds1302.NewShiftRegister(ser, srclk, rclk, 32)
+//     for _, rtc := range sr.Devices(clk, dat) {
+//         rtc.Init()
+//         rtc.SetTime(now)
+//     }
+//
+// Блокировка каждого DS1302 защищает только его собственный обмен:
+// чтобы работать с устройствами общей шины из разных горутин, задайте им
+// общий Config.BusLocker.
+type ShiftRegister struct {
+    ser, srclk, rclk Pin
+
+    mu    sync.Mutex
+    state []uint8 // Выходы: бит i%8 байта i/8
+}
+
+// NewShiftRegister создает цепочку регистров на линиях ser (данные),
+// srclk (сдвиг) и rclk (защелка) с n выходами (n округляется до кратного 8)
+// и сбрасывает все выходы в низкий уровень. Выход 0 - Q0 первой
+// микросхемы, к SER которой подключен МК; выход 8 - Q0 следующей.
+func NewShiftRegister(ser, srclk, rclk Pin, n int) *ShiftRegister {
+    s := &ShiftRegister{ser: ser, srclk: srclk, rclk: rclk, state: make([]uint8, (n+7)/8)}
+    ser.ConfigureOutput()
+    srclk.ConfigureOutput()
+    rclk.ConfigureOutput()
+    srclk.Low()
+    rclk.Low()
+    s.mu.Lock()
+    s.flush()
+    s.mu.Unlock()
+    return s
+}
+
+// Len возвращает число выходов цепочки.
+func (s *ShiftRegister) Len() int {
+    return len(s.state) * 8
+}
+
+// Pin возвращает выход i как линию Pin, например RST для New.
+// Изменение уровня сразу выводится в регистры.
+func (s *ShiftRegister) Pin(i int) Pin {
+    return &shiftPin{s: s, i: i}
+}
+
+// Devices создает DS1302 на общих линиях clk и dat с RST на каждом
+// выходе цепочки.
+func (s *ShiftRegister) Devices(clk, dat Pin) []*DS1302 {
+    devs := make([]*DS1302, s.Len())
+    for i := range devs {
+        devs[i] = New(clk, dat, s.Pin(i))
+    }
+    return devs
+}
+
+// set выставляет выход i и обновляет регистры.
+func (s *ShiftRegister) set(i int, level bool) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if i < 0 || i >= s.Len() {
+        return
+    }
+    if level {
+        s.state[i/8] |= 1 << (i % 8)
+    } else {
+        s.state[i/8] &^= 1 << (i % 8)
+    }
+    s.flush()
+}
+
+func (s *ShiftRegister) get(i int) bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return i >= 0 && i < s.Len() && s.state[i/8]&(1<<(i%8)) != 0
+}
+
+// flush вдвигает состояние, начиная со старшего выхода последней
+// микросхемы, и защелкивает его на выходах. 74HC595 работает на десятках
+// мегагерц, поэтому паузы между фронтами не нужны.
+func (s *ShiftRegister) flush() {
+    for r := len(s.state) - 1; r >= 0; r-- {
+        for b := 7; b >= 0; b-- {
+            if s.state[r]&(1<<b) != 0 {
+                s.ser.High()
+            } else {
+                s.ser.Low()
+            }
+            s.srclk.High()
+            s.srclk.Low()
+        }
+    }
+    s.rclk.High()
+    s.rclk.Low()
+}
+
+// shiftPin - выход ShiftRegister, удовлетворяющий Pin. Выход регистра
+// не умеет работать входом: ConfigureInput опускает линию, что для RST
+// означает то же, что освобождение шины.
+type shiftPin struct {
+    s *ShiftRegister
+    i int
+}
+
+func (p *shiftPin) ConfigureOutput() {}
+func (p *shiftPin) ConfigureInput()  { p.s.set(p.i, false) }
+func (p *shiftPin) High()            { p.s.set(p.i, true) }
+func (p *shiftPin) Low()             { p.s.set(p.i, false) }
+func (p *shiftPin) Get() bool        { return p.s.get(p.i) }
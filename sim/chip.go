@@ -0,0 +1,430 @@
+// Package sim моделирует DS1302 для тестов на хосте без железа.
+//
+// Chip - модель микросхемы на уровне линий CLK, DAT и RST: драйвер
+// работает с ней через обычные Pin, поэтому проверяется весь путь
+// от SetTime до регистров и обратно:
+//
+//     chip := sim.New()
+//     rtc := ds1302.New(chip.Pins())
+//     rtc.Init()
+//
+// VirtualClock - заглушка интерфейса ds1302.RTC без шины для тестов
+// прикладного кода, которым не важны подробности протокола.
+package sim
+
+import (
+    "sync"
+    "time"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+    "github.com/golangworker/ds1302-driver/bcd"
+)
+
+// Номера регистров часов в Chip.Registers.
+const (
+    regSeconds = iota
+    regMinutes
+    regHours
+    regDate
+    regMonth
+    regWeekday
+    regYear
+    regWP
+    regTrickle
+
+    registers
+)
+
+// regMask - биты регистров, которые хранит микросхема; остальные читаются нулями.
+var regMask = [registers]uint8{0xFF, 0x7F, 0xBF, 0x3F, 0x1F, 0x07, 0xFF, 0x80, 0xFF}
+
+// Поля адресного байта (см. ds1302.Command).
+const (
+    cmdBit   = 0x80
+    ramBit   = 0x40
+    readBit  = 0x01
+    burstIdx = 31
+)
+
+// Chip - модель DS1302: регистры часов, счет времени, защита от записи,
+// пакетный обмен и 31 байт ОЗУ. Как и настоящая микросхема, после
+// включения показывает 01.01.2000 00:00:00 с остановленным генератором.
+//
+// Одиночные чтения регистров не защелкиваются, поэтому модель воспроизводит
+// перенос между чтением минут и секунд; пакетное чтение часов, как
+// у микросхемы, берет все регистры в момент команды.
+type Chip struct {
+    // Now - источник времени модели (nil - time.Now). Тесты подставляют
+    // управляемые часы, чтобы не ждать настоящих секунд.
+    Now func() time.Time
+
+    mu   sync.Mutex
+    regs [registers]uint8
+    ram  [ds1302.RAMSize]uint8
+    last time.Time     // Момент последнего обновления счета
+    frac time.Duration // Накопленная доля секунды
+
+    drift   float64       // Уход кварца, ppm (см. SetDrift)
+    battery time.Duration // Остаток хода от батареи (см. SetBattery)
+    drain   bool          // Батарея разряжается
+
+    // Состояние линий и текущего обмена.
+    rst, clk, dat bool // Уровни линий, выставленные драйвером
+    datOutput     bool // DAT драйвера в режиме выхода
+    driving       bool // Микросхема выставляет данные на DAT
+    out           bool // Бит, выставленный микросхемой
+    ignore        bool // Адресный байт без бита 7: обмен игнорируется
+    haveCmd       bool
+    cmd           uint8
+    shift         uint8
+    nbits         int
+    index         int      // Номер байта данных в обмене
+    outByte       uint8
+    outPos        int
+    latch         [8]uint8 // Снимок регистров для пакетного чтения
+    burst         [8]uint8 // Принятые байты пакетной записи
+}
+
+// New создает модель в состоянии после первого включения.
+func New() *Chip {
+    c := &Chip{}
+    c.regs = [registers]uint8{0x80, 0x00, 0x00, 0x01, 0x01, 0x01, 0x00, 0x80, 0x5C}
+    return c
+}
+
+// Pins возвращает линии CLK, DAT и RST модели в порядке аргументов ds1302.New.
+func (c *Chip) Pins() (clk, dat, rst ds1302.Pin) {
+    return &pin{c, lineCLK}, &pin{c, lineDAT}, &pin{c, lineRST}
+}
+
+// Registers возвращает текущие значения регистров часов: секунды, минуты,
+// часы, дата, месяц, день недели, год, защита от записи, подзарядка.
+func (c *Chip) Registers() [9]uint8 {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.update()
+    return c.regs
+}
+
+// SetRegisters записывает регистры часов в обход протокола и защиты от записи,
+// например чтобы начать тест за секунду до переноса.
+func (c *Chip) SetRegisters(regs [9]uint8) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.update()
+    for i := range regs {
+        c.regs[i] = regs[i] & regMask[i]
+    }
+    c.frac = 0
+}
+
+// RAM возвращает содержимое ОЗУ модели.
+func (c *Chip) RAM() [ds1302.RAMSize]uint8 {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return c.ram
+}
+
+func (c *Chip) now() time.Time {
+    if c.Now != nil {
+        return c.Now()
+    }
+    return time.Now()
+}
+
+// update продвигает счет на время, прошедшее с прошлого обновления,
+// с учетом ухода кварца и разряда батареи.
+func (c *Chip) update() {
+    now := c.now()
+    if c.last.IsZero() || c.regs[regSeconds]&0x80 != 0 {
+        c.last = now
+        return
+    }
+    elapsed := now.Sub(c.last)
+    c.last = now
+    depleted := false
+    if c.drain {
+        if elapsed >= c.battery {
+            elapsed, depleted = c.battery, true
+        }
+        c.battery -= elapsed
+    }
+    c.frac += elapsed + time.Duration(float64(elapsed)*c.drift/1e6)
+    for c.frac >= time.Second {
+        c.tick()
+        c.frac -= time.Second
+    }
+    if depleted {
+        c.regs[regSeconds] |= 0x80
+        c.frac = 0
+        c.drain = false
+    }
+}
+
+// tick прибавляет секунду с переносами, как счетчики микросхемы.
+func (c *Chip) tick() {
+    r := &c.regs
+    if s := bcd.ToDec(r[regSeconds]&0x7F) + 1; s < 60 {
+        r[regSeconds] = bcd.FromDec(s)
+        return
+    }
+    r[regSeconds] = 0
+    if m := bcd.ToDec(r[regMinutes]) + 1; m < 60 {
+        r[regMinutes] = bcd.FromDec(m)
+        return
+    }
+    r[regMinutes] = 0
+
+    h := r[regHours]
+    if h&0x80 != 0 {
+        pm := h & 0x20
+        switch v := bcd.ToDec(h & 0x1F); v {
+        case 11:
+            // 11:59:59 PM -> 12:00:00 AM - новые сутки.
+            r[regHours] = 0x80 | (pm ^ 0x20) | bcd.FromDec(12)
+            if pm == 0 {
+                return
+            }
+        case 12:
+            r[regHours] = 0x80 | pm | bcd.FromDec(1)
+            return
+        default:
+            r[regHours] = 0x80 | pm | bcd.FromDec(v+1)
+            return
+        }
+    } else {
+        if v := bcd.ToDec(h&0x3F) + 1; v < 24 {
+            r[regHours] = bcd.FromDec(v)
+            return
+        }
+        r[regHours] = 0
+    }
+
+    if wd := r[regWeekday] + 1; wd <= 7 {
+        r[regWeekday] = wd
+    } else {
+        r[regWeekday] = 1
+    }
+    year := bcd.ToDec(r[regYear])
+    month := bcd.ToDec(r[regMonth])
+    if d := bcd.ToDec(r[regDate]) + 1; d <= daysIn(month, year) {
+        r[regDate] = bcd.FromDec(d)
+        return
+    }
+    r[regDate] = 0x01
+    if month < 12 {
+        r[regMonth] = bcd.FromDec(month + 1)
+        return
+    }
+    r[regMonth] = 0x01
+    r[regYear] = bcd.FromDec((year + 1) % 100)
+}
+
+// daysIn возвращает число дней в месяце; високосный каждый четвертый год,
+// как у микросхемы (2000-2099).
+func daysIn(month, year uint8) uint8 {
+    switch month {
+    case 2:
+        if year%4 == 0 {
+            return 29
+        }
+        return 28
+    case 4, 6, 9, 11:
+        return 30
+    }
+    return 31
+}
+
+// Линии модели.
+const (
+    lineCLK = iota
+    lineDAT
+    lineRST
+)
+
+// pin - линия модели, удовлетворяющая ds1302.Pin.
+type pin struct {
+    c    *Chip
+    line int
+}
+
+func (p *pin) ConfigureOutput() { p.c.configure(p.line, true) }
+func (p *pin) ConfigureInput()  { p.c.configure(p.line, false) }
+func (p *pin) High()            { p.c.set(p.line, true) }
+func (p *pin) Low()             { p.c.set(p.line, false) }
+func (p *pin) Get() bool        { return p.c.get(p.line) }
+
+func (c *Chip) configure(line int, output bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if line == lineDAT {
+        c.datOutput = output
+    }
+}
+
+func (c *Chip) get(line int) bool {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    switch line {
+    case lineCLK:
+        return c.clk
+    case lineRST:
+        return c.rst
+    }
+    if c.driving && !c.datOutput {
+        return c.out
+    }
+    return c.dat
+}
+
+func (c *Chip) set(line int, level bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    switch line {
+    case lineDAT:
+        c.dat = level
+    case lineRST:
+        if level && !c.rst {
+            c.begin()
+        }
+        if !level {
+            c.driving = false
+        }
+        c.rst = level
+    case lineCLK:
+        if c.rst && !c.ignore {
+            if level && !c.clk {
+                c.rise()
+            } else if !level && c.clk {
+                c.fall()
+            }
+        }
+        c.clk = level
+    }
+}
+
+// begin начинает обмен по фронту RST.
+func (c *Chip) begin() {
+    c.update()
+    c.haveCmd, c.ignore, c.driving = false, false, false
+    c.shift, c.nbits, c.index = 0, 0, 0
+}
+
+// rise обрабатывает фронт CLK: прием бита от драйвера.
+func (c *Chip) rise() {
+    if c.haveCmd && c.cmd&readBit != 0 {
+        return
+    }
+    if c.dat {
+        c.shift |= 1 << c.nbits
+    }
+    c.nbits++
+    if c.nbits < 8 {
+        return
+    }
+    b := c.shift
+    c.shift, c.nbits = 0, 0
+    if !c.haveCmd {
+        c.command(b)
+        return
+    }
+    c.write(c.index, b)
+    c.index++
+}
+
+// fall обрабатывает спад CLK: при чтении микросхема выставляет следующий бит.
+// Первый бит появляется по спаду восьмого такта адресного байта.
+func (c *Chip) fall() {
+    if !c.haveCmd || c.cmd&readBit == 0 {
+        return
+    }
+    c.outPos++
+    if c.outPos == 8 {
+        c.index++
+        c.outByte = c.read(c.index)
+        c.outPos = 0
+    }
+    c.out = c.outByte>>c.outPos&1 != 0
+    c.driving = true
+}
+
+// command разбирает адресный байт.
+func (c *Chip) command(b uint8) {
+    if b&cmdBit == 0 {
+        c.ignore = true
+        return
+    }
+    c.cmd, c.haveCmd = b, true
+    if b&readBit == 0 {
+        return
+    }
+    if b&ramBit == 0 && c.regIndex() == burstIdx {
+        copy(c.latch[:], c.regs[:8])
+    }
+    c.outByte = c.read(0)
+    c.outPos = -1
+}
+
+func (c *Chip) regIndex() int {
+    return int(c.cmd>>1) & 0x1F
+}
+
+// read возвращает i-й байт данных текущего обмена.
+func (c *Chip) read(i int) uint8 {
+    idx := c.regIndex()
+    if c.cmd&ramBit != 0 {
+        if idx == burstIdx {
+            idx = i
+        }
+        if idx < ds1302.RAMSize {
+            return c.ram[idx]
+        }
+        return 0
+    }
+    if idx == burstIdx {
+        if i < len(c.latch) {
+            return c.latch[i]
+        }
+        return 0
+    }
+    if idx < registers {
+        return c.regs[idx]
+    }
+    return 0
+}
+
+// write принимает i-й байт данных текущего обмена.
+func (c *Chip) write(i int, b uint8) {
+    idx := c.regIndex()
+    protected := c.regs[regWP]&0x80 != 0
+    if c.cmd&ramBit != 0 {
+        if idx == burstIdx {
+            idx = i
+        }
+        if idx < ds1302.RAMSize && !protected {
+            c.ram[idx] = b
+        }
+        return
+    }
+    if idx == burstIdx {
+        // Пакетная запись часов вступает в силу только после всех восьми байт.
+        if i >= len(c.burst) {
+            return
+        }
+        c.burst[i] = b
+        if i == len(c.burst)-1 && !protected {
+            for j, v := range c.burst {
+                c.regs[j] = v & regMask[j]
+            }
+            c.frac = 0
+        }
+        return
+    }
+    if idx >= registers || protected && idx != regWP {
+        return
+    }
+    c.regs[idx] = b & regMask[idx]
+    if idx == regSeconds {
+        c.frac = 0
+    }
+}
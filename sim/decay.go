@@ -0,0 +1,41 @@
+package sim
+
+import (
+    "time"
+)
+
+// SetDrift задает уход кварца в миллионных долях (ppm): положительный -
+// часы спешат, отрицательный - отстают. Типичный кварц 32768 Гц уходит
+// на ±20 ppm, то есть примерно на ±1,7 с в сутки. По умолчанию 0.
+// Время, прошедшее до вызова, засчитывается с прежним уходом.
+func (c *Chip) SetDrift(ppm float64) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.update()
+    c.drift = ppm
+}
+
+// SetBattery включает разряд батареи: через life хода генератора (по Now)
+// напряжение падает ниже порога генерации, и генератор останавливается
+// с установленным битом CH. Регистры и ОЗУ при этом сохраняются -
+// хранение данных требует меньшего напряжения, чем ход часов. Так
+// проверяется обнаружение потери питания (ds1302.DS1302.Trust).
+//
+// Пока генератор стоит, батарея не разряжается. После остановки разряд
+// выключается, как после замены батареи: генератор снова запускается
+// драйвером. life <= 0 выключает разряд (по умолчанию батарея не садится).
+func (c *Chip) SetBattery(life time.Duration) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.update()
+    c.battery, c.drain = life, life > 0
+}
+
+// Battery возвращает остаток хода от батареи и false, если разряд
+// выключен или батарея уже села.
+func (c *Chip) Battery() (time.Duration, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.update()
+    return c.battery, c.drain
+}
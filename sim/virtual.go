@@ -0,0 +1,115 @@
+package sim
+
+import (
+    "sync"
+    "time"
+
+    ds1302 "github.com/golangworker/ds1302-driver"
+)
+
+// VirtualClock - заглушка ds1302.RTC без шины: время идет по Now
+// с точностью до секунды, ОЗУ хранится в памяти. Нулевое значение готово
+// к работе и, как новая микросхема, показывает 01.01.2000 со стоящим
+// генератором, пока не вызван SetTime. Параметры Configure запоминаются,
+// но не влияют на поведение.
+type VirtualClock struct {
+    // Now - источник времени (nil - time.Now).
+    Now func() time.Time
+
+    mu   sync.Mutex
+    cfg  ds1302.Config
+    set  bool
+    base time.Time // Установленное время
+    at   time.Time // Момент установки по Now
+    ram  [ds1302.RAMSize]uint8
+}
+
+var _ ds1302.RTC = (*VirtualClock)(nil)
+
+func (v *VirtualClock) now() time.Time {
+    if v.Now != nil {
+        return v.Now()
+    }
+    return time.Now()
+}
+
+// Configure запоминает параметры.
+func (v *VirtualClock) Configure(cfg ds1302.Config) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    v.cfg = cfg
+}
+
+// SetTime устанавливает время и запускает часы.
+func (v *VirtualClock) SetTime(t time.Time) error {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    v.base = t.UTC().Truncate(time.Second)
+    v.at = v.now()
+    v.set = true
+    return nil
+}
+
+// ReadTime возвращает текущее время часов.
+func (v *VirtualClock) ReadTime() time.Time {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    return v.readTime()
+}
+
+func (v *VirtualClock) readTime() time.Time {
+    if !v.set {
+        return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+    }
+    return v.base.Add(v.now().Sub(v.at)).Truncate(time.Second)
+}
+
+// Status возвращает состояние часов в 24-часовом формате.
+func (v *VirtualClock) Status() (ds1302.Status, error) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    return ds1302.Status{
+        Time:           v.readTime(),
+        Running:        v.set,
+        WriteProtected: true,
+        HourMode:       ds1302.Hour24,
+        Trickle:        0x5C,
+    }, nil
+}
+
+// ReadRAM читает байт ОЗУ.
+func (v *VirtualClock) ReadRAM(addr uint8) (uint8, error) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    if addr >= ds1302.RAMSize {
+        return 0, ds1302.ErrRAMOutOfRange
+    }
+    return v.ram[addr], nil
+}
+
+// WriteRAM записывает байт ОЗУ.
+func (v *VirtualClock) WriteRAM(addr, value uint8) error {
+    return v.WriteRAMBytes(addr, []byte{value})
+}
+
+// ReadRAMBytes читает len(buf) байт ОЗУ начиная с addr.
+func (v *VirtualClock) ReadRAMBytes(addr uint8, buf []byte) error {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    if int(addr)+len(buf) > ds1302.RAMSize {
+        return ds1302.ErrRAMOutOfRange
+    }
+    copy(buf, v.ram[addr:])
+    return nil
+}
+
+// WriteRAMBytes записывает data в ОЗУ начиная с addr.
+func (v *VirtualClock) WriteRAMBytes(addr uint8, data []byte) error {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    if int(addr)+len(data) > ds1302.RAMSize {
+        return ds1302.ErrRAMOutOfRange
+    }
+    copy(v.ram[addr:], data)
+    return nil
+}
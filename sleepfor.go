@@ -0,0 +1,105 @@
+package ds1302
+
+import (
+    "context"
+    "time"
+)
+
+// Параметры SleepFor.
+const (
+    // sleepForChunk - наибольший отрезок сна между сверками с RTC.
+    sleepForChunk = 10 * time.Minute
+    // sleepForMargin - доля остатка, которую отрезок недосыпает: таймер МК,
+    // отстающий до 1/16 (6%), не проспит конец.
+    sleepForMargin = 16
+    // sleepForFinal - остаток, который досыпается опросом часов. Отрезок
+    // сна перед ним длиннее secondEdgeTimeout, поэтому после него часы
+    // обязаны показать новую секунду.
+    sleepForFinal = 3 * time.Second
+    // sleepForPoll - пауза между чтениями часов в последние секунды.
+    sleepForPoll = 10 * time.Millisecond
+)
+
+// SleepFor ждет d по часам DS1302, а не по таймеру МК. Длинная пауза
+// ("полить через 6 часов") по внутреннему RC-генератору МК ошибается на
+// минуты; SleepFor засыпает отрезками не длиннее 10 минут, после каждого
+// сверяет остаток с RTC и последние секунды опрашивает часы, так что
+// ожидание заканчивается в начале секунды RTC, на которую пришелся конец.
+//
+// Начало отсчитывается от текущего показания часов, поэтому фактическая
+// пауза короче d меньше чем на секунду. Если часы стоят, возвращается
+// ErrNoSecondEdge. Блокировка устройства берется только на чтения.
+func (d *DS1302) SleepFor(dur time.Duration) error {
+    return d.SleepForContext(context.Background(), dur)
+}
+
+// SleepForContext работает как SleepFor, но прерывается отменой ctx
+// и возвращает ctx.Err().
+func (d *DS1302) SleepForContext(ctx context.Context, dur time.Duration) error {
+    start, err := d.now()
+    if err != nil {
+        return err
+    }
+    end := start.Add(dur.Truncate(time.Second))
+    now := start
+    for now.Before(end) {
+        remain := end.Sub(now)
+        if remain <= sleepForFinal {
+            if now, err = d.nextSecond(ctx, now); err != nil {
+                return err
+            }
+            continue
+        }
+        chunk := remain - remain/sleepForMargin - time.Second
+        if chunk > sleepForChunk {
+            chunk = sleepForChunk
+        }
+        if err := sleepContext(ctx, chunk); err != nil {
+            return err
+        }
+        prev := now
+        if now, err = d.now(); err != nil {
+            return err
+        }
+        if !now.After(prev) {
+            d.mu.Lock()
+            defer d.mu.Unlock()
+            return d.fail(ErrNoSecondEdge)
+        }
+    }
+    return nil
+}
+
+// nextSecond опрашивает часы, пока их время не станет больше prev,
+// но не дольше secondEdgeTimeout.
+func (d *DS1302) nextSecond(ctx context.Context, prev time.Time) (time.Time, error) {
+    deadline := time.Now().Add(secondEdgeTimeout)
+    for time.Now().Before(deadline) {
+        if err := sleepContext(ctx, sleepForPoll); err != nil {
+            return time.Time{}, err
+        }
+        now, err := d.now()
+        if err != nil || now.After(prev) {
+            return now, err
+        }
+    }
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    return time.Time{}, d.fail(ErrNoSecondEdge)
+}
+
+// sleepContext спит dur или до отмены ctx.
+func sleepContext(ctx context.Context, dur time.Duration) error {
+    if ctx.Done() == nil {
+        time.Sleep(dur)
+        return nil
+    }
+    t := time.NewTimer(dur)
+    defer t.Stop()
+    select {
+    case <-ctx.Done():
+        return ctx.Err()
+    case <-t.C:
+        return nil
+    }
+}
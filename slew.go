@@ -0,0 +1,89 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// Параметры Slewer по умолчанию.
+const (
+    DefaultSlewInterval = 10 * time.Second // Одна секунда коррекции за 10 секунд (10%)
+    DefaultMaxSlew      = 10 * time.Minute // Большие расхождения Syncer исправляет сразу
+)
+
+// Slewer плавно исправляет время DS1302 вместо одномоментной перестановки.
+//
+// Микросхема не позволяет менять скорость хода, поэтому коррекция
+// выполняется шагами по одной секунде раз в Interval. Шаг делается сразу
+// после смены секунды: при опережении часов текущая секунда повторяется,
+// при отставании - пропускается. Метки времени в журнале при этом
+// никогда не идут назад, даже когда NTP присылает поправку в 30 секунд.
+type Slewer struct {
+    RTC      *DS1302
+    Interval time.Duration // Период шагов коррекции (0 - DefaultSlewInterval)
+
+    pending int64     // Оставшаяся коррекция в секундах (со знаком)
+    last    time.Time // Момент последнего шага (монотонные часы МК)
+}
+
+// Adjust добавляет к оставшейся коррекции offset (округляется до секунд).
+// Положительное значение означает, что часы отстают.
+func (s *Slewer) Adjust(offset time.Duration) {
+    s.pending += int64(offset.Round(time.Second) / time.Second)
+}
+
+// Pending возвращает оставшуюся коррекцию.
+func (s *Slewer) Pending() time.Duration {
+    return time.Duration(s.pending) * time.Second
+}
+
+// Update выполняет очередной шаг коррекции, если он назрел.
+// Вызывайте регулярно из главного цикла. Шаг ожидает смены секунды,
+// а при опережении часов еще и держит генератор остановленным секунду,
+// поэтому может блокировать выполнение (и другие обращения к часам)
+// до двух секунд. Смена секунды и запись выполняются под одной
+// блокировкой устройства, так что никто не прочитает время между ними.
+// Возвращает true, если шаг был выполнен; при остановленном генераторе
+// или закрытом устройстве шаг не выполняется.
+func (s *Slewer) Update() bool {
+    if s.pending == 0 {
+        return false
+    }
+    interval := s.Interval
+    if interval == 0 {
+        interval = DefaultSlewInterval
+    }
+    if !s.last.IsZero() && time.Since(s.last) < interval {
+        return false
+    }
+    
+    d := s.RTC
+    d.mu.Lock()
+    defer d.unlock()
+    if d.checkOpen() != nil {
+        return false
+    }
+    t, edge, err := d.waitSecondEdge()
+    if err != nil {
+        return false
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    if s.pending > 0 {
+        // Часы отстают: текущая секунда пропускается.
+        d.writeTime(t.Add(time.Second))
+        d.noteTimeSet(t, nil, t.Add(time.Second), AdjustSlew)
+        s.pending--
+    } else {
+        // Часы спешат: генератор стоит секунду, и текущая секунда
+        // повторяется. Запись t-1 показала бы время, идущее назад.
+        sec := d.readRegister(DS1302_SECONDS_READ) &^ DS1302_CH_BIT
+        d.writeRegister(DS1302_SECONDS_WRITE, sec|DS1302_CH_BIT)
+        time.Sleep(time.Second - time.Since(edge))
+        d.writeRegister(DS1302_SECONDS_WRITE, sec)
+        d.noteTimeSet(t.Add(time.Second), nil, t, AdjustSlew)
+        s.pending++
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x80)
+    
+    s.last = time.Now()
+    return true
+}
@@ -0,0 +1,50 @@
+package ds1302
+
+import (
+    "context"
+    "encoding/hex"
+    "errors"
+)
+
+// ErrBadSnapshot возвращается ImportRAM, если снимок поврежден
+// (неверная длина, не шестнадцатеричные символы или не совпала CRC).
+var ErrBadSnapshot = errors.New("ds1302: bad RAM snapshot")
+
+// snapshotLen - длина снимка ОЗУ в символах: 31 байт и CRC-16.
+const snapshotLen = (RAMSize + 2) * 2
+
+// ExportRAM возвращает снимок всего резервного ОЗУ в виде строки
+// из 66 шестнадцатеричных символов: 31 байт ОЗУ и CRC-16/CCITT-FALSE
+// (big-endian). Строку удобно передать по UART или MQTT и восстановить
+// через ImportRAM на модуле, заменившем неисправный. Если ОЗУ прочитать
+// не удалось (например, устройство закрыто), возвращается пустая строка,
+// которую ImportRAM отвергает.
+func (d *DS1302) ExportRAM() string {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    var buf [RAMSize + 2]byte
+    if err := d.readBurstContext(context.Background(), RAMBurstRead, buf[:RAMSize]); err != nil {
+        return ""
+    }
+    crc := crc16(buf[:RAMSize])
+    buf[RAMSize], buf[RAMSize+1] = byte(crc>>8), byte(crc)
+    return hex.EncodeToString(buf[:])
+}
+
+// ImportRAM проверяет снимок, полученный от ExportRAM, и записывает
+// его в резервное ОЗУ. Поврежденный снимок не записывается.
+func (d *DS1302) ImportRAM(snapshot string) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if len(snapshot) != snapshotLen {
+        return d.fail(ErrBadSnapshot)
+    }
+    var buf [RAMSize + 2]byte
+    if _, err := hex.Decode(buf[:], []byte(snapshot)); err != nil {
+        return d.fail(ErrBadSnapshot)
+    }
+    if crc16(buf[:RAMSize]) != uint16(buf[RAMSize])<<8|uint16(buf[RAMSize+1]) {
+        return d.fail(ErrBadSnapshot)
+    }
+    return d.writeRAMBytes(0, buf[:RAMSize])
+}
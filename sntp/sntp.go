@@ -0,0 +1,116 @@
+// Package sntp реализует минимальный клиент SNTP (RFC 4330) для
+// синхронизации DS1302 по сети. Source удовлетворяет интерфейсу
+// ds1302.TimeSource и работает как в обычном Go, так и в TinyGo
+// с сетевым стеком netdev.
+package sntp
+
+import (
+    "encoding/binary"
+    "errors"
+    "io"
+    "net"
+    "time"
+)
+
+// packetSize - размер пакета NTP без расширений.
+const packetSize = 48
+
+// ntpEpochOffset - число секунд между 1900-01-01 и 1970-01-01.
+const ntpEpochOffset = 2208988800
+
+var (
+    // ErrShortPacket возвращается, если ответ сервера короче 48 байт.
+    ErrShortPacket = errors.New("sntp: short packet")
+    // ErrBadReply возвращается, если ответ не соответствует запросу или сервер не синхронизирован.
+    ErrBadReply = errors.New("sntp: invalid server reply")
+    // ErrKissOfDeath возвращается, если сервер просит прекратить запросы (stratum 0).
+    ErrKissOfDeath = errors.New("sntp: kiss-of-death reply")
+)
+
+// DefaultTimeout - время ожидания ответа сервера по умолчанию.
+const DefaultTimeout = 5 * time.Second
+
+// Source - источник времени, опрашивающий NTP сервер по UDP.
+type Source struct {
+    Server  string        // Адрес сервера, например "pool.ntp.org:123"
+    Timeout time.Duration // Время ожидания ответа (0 - DefaultTimeout)
+}
+
+// Now запрашивает время у сервера.
+func (s Source) Now() (time.Time, error) {
+    conn, err := net.Dial("udp", s.Server)
+    if err != nil {
+        return time.Time{}, err
+    }
+    defer conn.Close()
+
+    timeout := s.Timeout
+    if timeout == 0 {
+        timeout = DefaultTimeout
+    }
+    if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+        return time.Time{}, err
+    }
+    return Query(conn)
+}
+
+// Query отправляет один запрос SNTP через conn и возвращает время сервера,
+// скорректированное на половину задержки в сети.
+func Query(conn io.ReadWriter) (time.Time, error) {
+    var req [packetSize]byte
+    req[0] = 0<<6 | 4<<3 | 3 // LI = 0, версия 4, режим 3 (клиент)
+
+    t1 := time.Now()
+    putTimestamp(req[40:], t1)
+    if _, err := conn.Write(req[:]); err != nil {
+        return time.Time{}, err
+    }
+
+    var resp [packetSize]byte
+    n, err := conn.Read(resp[:])
+    if err != nil {
+        return time.Time{}, err
+    }
+    t4 := time.Now()
+    if n < packetSize {
+        return time.Time{}, ErrShortPacket
+    }
+
+    mode := resp[0] & 0x07
+    li := resp[0] >> 6
+    stratum := resp[1]
+    switch {
+    case mode != 4 && mode != 5:
+        return time.Time{}, ErrBadReply
+    case stratum == 0:
+        return time.Time{}, ErrKissOfDeath
+    case li == 3 || stratum > 15:
+        return time.Time{}, ErrBadReply
+    case binary.BigEndian.Uint64(resp[24:]) != binary.BigEndian.Uint64(req[40:]):
+        return time.Time{}, ErrBadReply
+    }
+
+    t2 := timestamp(resp[32:])
+    t3 := timestamp(resp[40:])
+    offset := (t2.Sub(t1) + t3.Sub(t4)) / 2
+    return t4.Add(offset).UTC(), nil
+}
+
+// putTimestamp записывает t в формате временной метки NTP (32.32 бита).
+func putTimestamp(b []byte, t time.Time) {
+    sec := uint64(t.Unix() + ntpEpochOffset)
+    frac := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+    binary.BigEndian.PutUint64(b, sec<<32|frac)
+}
+
+// timestamp декодирует временную метку NTP. Метки со старшим битом секунд,
+// равным нулю, относятся к эре, начавшейся в 2036 году.
+func timestamp(b []byte) time.Time {
+    v := binary.BigEndian.Uint64(b)
+    sec := int64(v >> 32)
+    if sec&0x80000000 == 0 {
+        sec += 1 << 32
+    }
+    nsec := int64((v & 0xFFFFFFFF) * uint64(time.Second) >> 32)
+    return time.Unix(sec-ntpEpochOffset, nsec)
+}
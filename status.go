@@ -0,0 +1,48 @@
+package ds1302
+
+import (
+    "time"
+
+    "github.com/golangworker/ds1302-driver/codec"
+)
+
+// HourMode - формат хранения часов в DS1302.
+type HourMode = codec.HourMode
+
+const (
+    Hour24 = codec.Hour24 // 24-часовой формат (0-23)
+    Hour12 = codec.Hour12 // 12-часовой формат с битом AM/PM
+)
+
+// Status - снимок состояния DS1302.
+type Status struct {
+    Time           time.Time // Текущее время
+    Running        bool      // Генератор работает (бит CH сброшен)
+    WriteProtected bool      // Включена защита от записи
+    HourMode       HourMode  // Формат хранения часов
+    Trickle        uint8     // Сырое значение регистра подзарядки
+}
+
+// Status читает состояние часов: все регистры часов одной пакетной
+// операцией и регистр подзарядки. Отвечает на вопрос
+// "в каком состоянии этот RTC?" для панелей и консольных команд.
+func (d *DS1302) Status() (Status, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return Status{}, err
+    }
+    var burst [8]uint8
+    d.readBurst(ClockBurstRead, burst[:])
+    trickle := d.readRegister(uint8(ClockRead(RegTrickle)))
+    
+    t, err := decodeTime([6]uint8{burst[0], burst[1], burst[2], burst[3], burst[4], burst[6]})
+    st := Status{
+        Time:           t,
+        Running:        burst[0]&DS1302_CH_BIT == 0,
+        WriteProtected: burst[7]&DS1302_WP_BIT != 0,
+        HourMode:       codec.HourModeOf(burst[2]),
+        Trickle:        trickle,
+    }
+    return st, d.fail(err)
+}
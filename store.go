@@ -0,0 +1,88 @@
+package ds1302
+
+import (
+    "bytes"
+    "encoding/binary"
+    "errors"
+)
+
+// Ошибки Store и Load.
+var (
+    // ErrStoreSize возвращается, если значение не фиксированного размера
+    // или вместе со служебными байтами не помещается в ОЗУ.
+    ErrStoreSize = errors.New("ds1302: value does not fit in RAM record")
+    // ErrStoreCorrupt возвращается Load, если запись не сохранялась,
+    // повреждена или сохранена для структуры другого размера.
+    ErrStoreCorrupt = errors.New("ds1302: RAM record is missing or corrupt")
+)
+
+// StoreOverhead - служебные байты записи Store: длина и CRC-16.
+const StoreOverhead = 3
+
+// Store сохраняет структуру фиксированного размера в начало резервного
+// ОЗУ (см. StoreAt). Сохранение состояния превращается в две строки:
+//
+//     var st struct{ Mode uint8; Setpoint int16 }
+//     if err := rtc.Load(&st); err != nil { st = defaults }
+//     ...
+//     rtc.Store(&st)
+func (d *DS1302) Store(v any) error {
+    return d.StoreAt(0, v)
+}
+
+// Load читает структуру, сохраненную Store.
+func (d *DS1302) Load(v any) error {
+    return d.LoadAt(0, v)
+}
+
+// StoreAt кодирует v через encoding/binary (little-endian) и записывает
+// в ОЗУ по адресу addr: байт длины, данные и CRC-16/CCITT-FALSE
+// (big-endian) по длине и данным. Запись занимает binary.Size(v)+StoreOverhead байт.
+func (d *DS1302) StoreAt(addr uint8, v any) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    n := binary.Size(v)
+    if n < 0 || int(addr)+n+StoreOverhead > RAMSize {
+        return d.fail(ErrStoreSize)
+    }
+    var buf [RAMSize]byte
+    w := sliceWriter(buf[1:1])
+    if err := binary.Write(&w, binary.LittleEndian, v); err != nil {
+        return err
+    }
+    buf[0] = uint8(n)
+    crc := crc16(buf[:1+n])
+    buf[1+n], buf[2+n] = byte(crc>>8), byte(crc)
+    return d.writeRAMBytes(addr, buf[:n+StoreOverhead])
+}
+
+// LoadAt читает в v запись, сохраненную StoreAt по адресу addr.
+// Если запись повреждена или ее длина не совпадает с размером v,
+// возвращает ErrStoreCorrupt и не изменяет v.
+func (d *DS1302) LoadAt(addr uint8, v any) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    n := binary.Size(v)
+    if n < 0 || int(addr)+n+StoreOverhead > RAMSize {
+        return d.fail(ErrStoreSize)
+    }
+    var buf [RAMSize]byte
+    rec := buf[:n+StoreOverhead]
+    if err := d.readRAMBytes(addr, rec); err != nil {
+        return err
+    }
+    crc := crc16(rec[:1+n])
+    if rec[0] != uint8(n) || rec[1+n] != byte(crc>>8) || rec[2+n] != byte(crc) {
+        return d.fail(ErrStoreCorrupt)
+    }
+    return binary.Read(bytes.NewReader(rec[1:1+n]), binary.LittleEndian, v)
+}
+
+// sliceWriter дописывает данные в срез без выделения памяти,
+// пока хватает емкости.
+type sliceWriter []byte
+
+func (w *sliceWriter) Write(p []byte) (int, error) {
+    *w = append(*w, p...)
+    return len(p), nil
+}
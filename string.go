@@ -0,0 +1,66 @@
+package ds1302
+
+import (
+    "strconv"
+    "time"
+)
+
+// String возвращает однострочную сводку для отладки, например
+// "DS1302 2025-01-07T12:30:05Z running wp=on trickle=off sync=3h ago".
+// Читает регистры часов, поэтому обращается к шине.
+func (d *DS1302) String() string {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if pe, ok := d.pinErr.(*PinError); ok {
+        return "DS1302 " + pe.Line + " " + pe.Err.Error()
+    }
+    if d.closed {
+        return "DS1302 closed"
+    }
+
+    var burst [8]uint8
+    d.readBurst(ClockBurstRead, burst[:])
+    trickle := d.readRegister(uint8(ClockRead(RegTrickle)))
+    t, err := decodeTime([6]uint8{burst[0], burst[1], burst[2], burst[3], burst[4], burst[6]})
+
+    s := "DS1302 "
+    if err != nil {
+        s += "invalid-time"
+    } else {
+        s += t.Format(time.RFC3339)
+    }
+    if burst[0]&DS1302_CH_BIT != 0 {
+        s += " halted"
+    } else {
+        s += " running"
+    }
+    s += " wp=" + onOff(burst[7]&DS1302_WP_BIT != 0)
+    s += " trickle=" + onOff(trickle&0xF0 == 0xA0)
+    if d.lastSet.IsZero() || err != nil {
+        return s + " sync=never"
+    }
+    return s + " sync=" + shortDuration(t.Sub(d.lastSet.Truncate(time.Second))) + " ago"
+}
+
+func onOff(on bool) string {
+    if on {
+        return "on"
+    }
+    return "off"
+}
+
+// shortDuration округляет d до одной крупной единицы: 45s, 12m, 3h, 5d.
+func shortDuration(d time.Duration) string {
+    if d < 0 {
+        d = 0
+    }
+    switch {
+    case d < time.Minute:
+        return strconv.Itoa(int(d/time.Second)) + "s"
+    case d < time.Hour:
+        return strconv.Itoa(int(d/time.Minute)) + "m"
+    case d < 48*time.Hour:
+        return strconv.Itoa(int(d/time.Hour)) + "h"
+    }
+    return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+}
@@ -0,0 +1,62 @@
+package ds1302
+
+import (
+    "math"
+    "time"
+)
+
+// SolarEvent - солнечное событие для расписания.
+type SolarEvent uint8
+
+const (
+    Sunrise SolarEvent = iota + 1 // Восход
+    Sunset                        // Заход
+)
+
+// String возвращает название события.
+func (e SolarEvent) String() string {
+    switch e {
+    case Sunrise:
+        return "sunrise"
+    case Sunset:
+        return "sunset"
+    }
+    return "none"
+}
+
+// j2000 - эпоха J2000.0 (2000-01-01 12:00 UTC).
+var j2000 = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// SunTimes вычисляет время восхода и захода Солнца в UTC для календарной
+// даты date (берутся год, месяц и день в зоне date) в точке с широтой lat
+// и долготой lon в градусах (север и восток положительны). Точность -
+// около минуты. ok равно false во время полярного дня или ночи.
+func SunTimes(date time.Time, lat, lon float64) (sunrise, sunset time.Time, ok bool) {
+    const rad = math.Pi / 180
+    
+    y, m, d := date.Date()
+    noon := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+    n := math.Round(noon.Sub(j2000).Hours() / 24)
+    
+    // Уравнение восхода: средний солнечный полдень, аномалия,
+    // эклиптическая долгота и склонение Солнца.
+    jStar := n + 0.0009 - lon/360
+    M := math.Mod(357.5291+0.98560028*jStar, 360)
+    C := 1.9148*math.Sin(M*rad) + 0.02*math.Sin(2*M*rad) + 0.0003*math.Sin(3*M*rad)
+    lambda := math.Mod(M+C+180+102.9372, 360)
+    transit := jStar + 0.0053*math.Sin(M*rad) - 0.0069*math.Sin(2*lambda*rad)
+    sinDecl := math.Sin(lambda*rad) * math.Sin(23.4397*rad)
+    cosDecl := math.Cos(math.Asin(sinDecl))
+    
+    // -0.833° учитывает рефракцию и радиус диска.
+    cosW := (math.Sin(-0.833*rad) - math.Sin(lat*rad)*sinDecl) / (math.Cos(lat*rad) * cosDecl)
+    if cosW < -1 || cosW > 1 {
+        return time.Time{}, time.Time{}, false
+    }
+    w := math.Acos(cosW) / rad / 360
+    
+    at := func(days float64) time.Time {
+        return j2000.Add(time.Duration(days * 24 * float64(time.Hour))).Round(time.Second)
+    }
+    return at(transit - w), at(transit + w), true
+}
@@ -0,0 +1,161 @@
+package ds1302
+
+import (
+    "encoding/binary"
+    "time"
+)
+
+// TamperStateSize - число байт ОЗУ, занимаемых TamperDetector:
+// последнее виденное время (4 байта) и проверочный байт.
+const TamperStateSize = 5
+
+// Параметры TamperDetector по умолчанию.
+const (
+    DefaultTamperTolerance = 2 * time.Second // Допуск на разрешение RTC и задержки опроса
+    DefaultTamperMaxPPM    = 20000           // Погрешность генератора МК (2%)
+)
+
+// tamperMagic маскирует проверочный байт последнего виденного времени.
+const tamperMagic = 0xC3
+
+// TamperKind - вид подозрительного изменения времени.
+type TamperKind uint8
+
+const (
+    TamperJump      TamperKind = iota + 1 // Скачок между двумя проверками, не объяснимый ходом часов МК
+    TamperBackwards                       // Время RTC раньше последнего виденного в ОЗУ
+    TamperRAMLost                         // Последнее виденное время потеряно: пропадало питание батареи
+)
+
+// String возвращает название вида события.
+func (k TamperKind) String() string {
+    switch k {
+    case TamperJump:
+        return "jump"
+    case TamperBackwards:
+        return "backwards"
+    case TamperRAMLost:
+        return "ram-lost"
+    }
+    return "unknown"
+}
+
+// TamperEvent описывает обнаруженное изменение времени.
+type TamperEvent struct {
+    Kind TamperKind
+    Prev time.Time     // Ожидаемое или последнее виденное время (нулевое для TamperRAMLost)
+    Now  time.Time     // Время RTC при проверке
+    Jump time.Duration // Now минус Prev
+}
+
+// TamperConfig задает параметры TamperDetector.
+type TamperConfig struct {
+    Addr      uint8         // Адрес последнего виденного времени в ОЗУ (TamperStateSize байт)
+    Tolerance time.Duration // Допустимое расхождение (0 - DefaultTamperTolerance)
+    MaxPPM    uint32        // Допустимая погрешность часов МК в ppm (0 - DefaultTamperMaxPPM)
+
+    OnTamper func(TamperEvent) // Вызывается при каждом обнаруженном событии
+}
+
+// TamperDetector замечает неправдоподобные скачки времени: между
+// соседними проверками (больше, чем объясняет ход часов МК) и между RTC
+// и последним виденным временем в ОЗУ. Позволяет устройствам с требованиями
+// к безопасности заметить снятие батареи и ручной перевод часов.
+// Перестановки через сам драйвер (SetTime, Syncer и т.п.) событиями не считаются.
+type TamperDetector struct {
+    dev *DS1302
+    cfg TamperConfig
+
+    prev    time.Time // Время RTC при прошлой проверке
+    prevMCU time.Time // Момент прошлой проверки по часам МК
+    setSeq  uint32    // Счетчик установок драйвера при прошлой проверке
+    started bool
+}
+
+// NewTamperDetector создает детектор с параметрами cfg.
+// Вызывайте Check периодически, например из главного цикла.
+func NewTamperDetector(d *DS1302, cfg TamperConfig) *TamperDetector {
+    return &TamperDetector{dev: d, cfg: cfg}
+}
+
+// Check читает часы, сравнивает их с прошлой проверкой и с последним
+// виденным временем в ОЗУ, вызывает OnTamper для обнаруженных событий
+// и сохраняет текущее время в ОЗУ. Первый вызов после запуска сверяется
+// только с ОЗУ; на новом модуле он сообщит TamperRAMLost.
+func (t *TamperDetector) Check() error {
+    events, err := t.check()
+    for _, e := range events {
+        t.report(e)
+    }
+    return err
+}
+
+// check выполняет проверку под блокировкой устройства и возвращает
+// обнаруженные события; OnTamper вызывается уже после снятия блокировки,
+// чтобы обработчик мог обращаться к DS1302.
+func (t *TamperDetector) check() ([]TamperEvent, error) {
+    t.dev.mu.Lock()
+    defer t.dev.mu.Unlock()
+    now, err := t.dev.readTime()
+    if err != nil {
+        return nil, err
+    }
+    mcu := time.Now()
+
+    var buf [TamperStateSize]byte
+    if err := t.dev.readRAMBytes(t.cfg.Addr, buf[:]); err != nil {
+        return nil, err
+    }
+    var events []TamperEvent
+    setByDriver := t.dev.setSeq != t.setSeq
+    if buf[4] != tamperCheck(buf[:4]) {
+        events = append(events, TamperEvent{Kind: TamperRAMLost, Now: now})
+    } else if seen := Unpack(binary.LittleEndian.Uint32(buf[:4])); now.Before(seen) && !setByDriver {
+        events = append(events, TamperEvent{Kind: TamperBackwards, Prev: seen, Now: now, Jump: now.Sub(seen)})
+    }
+
+    if t.started && !setByDriver {
+        elapsed := mcu.Sub(t.prevMCU)
+        expected := t.prev.Add(elapsed)
+        jump := now.Sub(expected)
+        if jump < 0 {
+            jump = -jump
+        }
+        if jump > t.allowed(elapsed) {
+            events = append(events, TamperEvent{Kind: TamperJump, Prev: expected, Now: now, Jump: now.Sub(expected)})
+        }
+    }
+
+    t.prev, t.prevMCU, t.setSeq, t.started = now, mcu, t.dev.setSeq, true
+    binary.LittleEndian.PutUint32(buf[:4], Pack(now))
+    buf[4] = tamperCheck(buf[:4])
+    return events, t.dev.writeRAMBytes(t.cfg.Addr, buf[:])
+}
+
+// allowed возвращает допустимое расхождение после elapsed по часам МК.
+func (t *TamperDetector) allowed(elapsed time.Duration) time.Duration {
+    tolerance := t.cfg.Tolerance
+    if tolerance == 0 {
+        tolerance = DefaultTamperTolerance
+    }
+    ppm := t.cfg.MaxPPM
+    if ppm == 0 {
+        ppm = DefaultTamperMaxPPM
+    }
+    return tolerance + elapsed/1000000*time.Duration(ppm)
+}
+
+func (t *TamperDetector) report(ev TamperEvent) {
+    if t.cfg.OnTamper != nil {
+        t.cfg.OnTamper(ev)
+    }
+}
+
+// tamperCheck вычисляет проверочный байт последнего виденного времени.
+func tamperCheck(b []byte) byte {
+    c := byte(tamperMagic)
+    for _, v := range b {
+        c ^= v
+    }
+    return c
+}
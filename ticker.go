@@ -0,0 +1,97 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// Ticker - замена time.Ticker для длинных интервалов (минуты - часы).
+// Каждый тик привязывается к часам RTC, поэтому "каждые 15 минут"
+// остаются выровненными по четвертям часа и не уплывают вместе
+// с генератором МК.
+//
+// Тикер обращается к DS1302 из собственной горутины; методы DS1302
+// берут внутреннюю блокировку, поэтому с теми же часами можно работать
+// из других горутин без дополнительной синхронизации.
+type Ticker struct {
+    C <-chan time.Time // Канал тиков; значение - время RTC в момент тика
+
+    c        chan time.Time
+    rtc      *DS1302
+    interval time.Duration
+    stop     chan struct{}
+}
+
+// NewTicker создает тикер с периодом interval, тики которого приходятся
+// на границы interval по времени RTC (для 15 минут - :00, :15, :30, :45).
+// Как и time.NewTicker, паникует при interval <= 0; как и у time.Ticker,
+// тики, которые никто не успел принять, пропускаются.
+func NewTicker(d *DS1302, interval time.Duration) *Ticker {
+    if interval <= 0 {
+        panic("ds1302: non-positive interval for NewTicker")
+    }
+    c := make(chan time.Time, 1)
+    t := &Ticker{
+        C:        c,
+        c:        c,
+        rtc:      d,
+        interval: interval,
+        stop:     make(chan struct{}),
+    }
+    go t.run()
+    return t
+}
+
+// Stop останавливает тикер. Канал C не закрывается.
+// Остановка может занять до одной секунды.
+func (t *Ticker) Stop() {
+    select {
+    case <-t.stop:
+    default:
+        close(t.stop)
+    }
+}
+
+func (t *Ticker) run() {
+    due := t.rtc.ReadTime().Truncate(t.interval).Add(t.interval)
+    for {
+        now := t.rtc.ReadTime()
+        if !now.Before(due) {
+            select {
+            case t.c <- now:
+            default:
+            }
+            due = now.Truncate(t.interval).Add(t.interval)
+            continue
+        }
+        
+        // Спим по часам МК с запасом на их погрешность и затем
+        // снова сверяемся с RTC; последнюю секунду ждем смены регистра.
+        remaining := due.Sub(now)
+        margin := remaining / 16
+        if margin < time.Second {
+            margin = time.Second
+        }
+        if wait := remaining - margin; wait > 0 {
+            select {
+            case <-t.stop:
+                return
+            case <-time.After(wait):
+            }
+            continue
+        }
+        
+        select {
+        case <-t.stop:
+            return
+        default:
+        }
+        if _, err := t.rtc.WaitForSecondEdge(); err != nil {
+            // Генератор стоит: повторяем попытку, не нагружая шину.
+            select {
+            case <-t.stop:
+                return
+            case <-time.After(time.Second):
+            }
+        }
+    }
+}
@@ -0,0 +1,60 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// timeSetFunc - подписчик OnTimeSet.
+type timeSetFunc = func(old, new time.Time, source string)
+
+// timeSet - запись времени, о которой еще не сообщено подписчикам OnTimeSet.
+type timeSet struct {
+    old, new time.Time
+    src      AdjustSource
+}
+
+// OnTimeSet подписывает fn на каждую запись времени в часы через драйвер:
+// SetTime и его варианты, Tx.SetTime, перестановки Syncer, шаги Slewer
+// и команду set утилиты ds1302ctl. Зависимые подсистемы (планировщики,
+// Interpolator, журналы) могут сразу перестроиться, а не обнаруживать
+// скачок времени позже.
+//
+// old - время часов перед записью (нулевое, если оно было нечитаемым),
+// new - записанное время, source - название источника, как у
+// AdjustSource.String: "manual", "forced", "aligned", "sync", "slew", "user".
+// Решение Syncer исправить часы плавно само по себе время не меняет:
+// о каждом шаге Slewer сообщается отдельно.
+//
+// fn вызывается после освобождения устройства, поэтому может читать
+// часы и ОЗУ, но не должна блокироваться надолго. Внутри Transaction
+// уведомления откладываются до ее завершения.
+func (d *DS1302) OnTimeSet(fn func(old, new time.Time, source string)) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    d.onTimeSet = append(d.onTimeSet, fn)
+}
+
+// noteTimeSet откладывает уведомление OnTimeSet до unlock.
+func (d *DS1302) noteTimeSet(old time.Time, oldErr error, t time.Time, src AdjustSource) {
+    if len(d.onTimeSet) == 0 {
+        return
+    }
+    if oldErr != nil {
+        old = time.Time{}
+    }
+    d.timeSets = append(d.timeSets, timeSet{old, t.Truncate(time.Second), src})
+}
+
+// unlock освобождает d.mu и вызывает подписчиков OnTimeSet для отложенных
+// записей времени. Методы, которые могут записать время, освобождают
+// устройство через unlock вместо d.mu.Unlock.
+func (d *DS1302) unlock() {
+    events, handlers := d.timeSets, d.onTimeSet
+    d.timeSets = nil
+    d.mu.Unlock()
+    for _, e := range events {
+        for _, fn := range handlers {
+            fn(e.old, e.new, e.src.String())
+        }
+    }
+}
@@ -0,0 +1,141 @@
+package ds1302
+
+import (
+    "sync"
+    "time"
+)
+
+// TimeSource - внешний источник точного времени (NTP, GPS, хост и т.п.).
+type TimeSource interface {
+    // Now возвращает текущее время источника.
+    Now() (time.Time, error)
+}
+
+// Параметры Syncer по умолчанию.
+const (
+    DefaultSyncInterval  = 24 * time.Hour // Период синхронизации
+    DefaultSyncThreshold = time.Second    // Минимальное расхождение, при котором часы переставляются
+)
+
+// Syncer подстраивает DS1302 по внешнему источнику времени.
+//
+// Между синхронизациями приложение читает время прямо из RTC,
+// а Syncer лишь время от времени (раз в Interval) сверяет часы с источником.
+// LastSync и LastOffset можно вызывать из других горутин во время Sync.
+type Syncer struct {
+    RTC       *DS1302
+    Source    TimeSource
+    Interval  time.Duration // Период синхронизации (0 - DefaultSyncInterval)
+    Threshold time.Duration // Порог перестановки часов (0 - DefaultSyncThreshold)
+
+    // Slewer, если задан, получает поправки не больше MaxSlew для плавной
+    // коррекции вместо перестановки часов (см. Slewer.Update).
+    Slewer  *Slewer
+    MaxSlew time.Duration // Наибольшая плавная поправка (0 - DefaultMaxSlew)
+
+    // Force разрешает переводить часы назад при включенной GuardBackwards.
+    Force bool
+
+    mu         sync.Mutex    // Защищает поля ниже: их читают, например, HTTP-обработчики метрик
+    lastSync   time.Time     // Время RTC при последней успешной синхронизации
+    lastOffset time.Duration // Расхождение при последней успешной синхронизации
+    synced     bool
+}
+
+// Sync сверяет RTC с источником и переставляет часы, если расхождение
+// не меньше Threshold. При заданном Slewer поправки до MaxSlew
+// исправляются плавно. Возвращает расхождение источник минус RTC;
+// если перестановка отклонена (ErrTimeBackwards), вместе с ним
+// возвращается ошибка, и синхронизация не считается выполненной.
+func (s *Syncer) Sync() (time.Duration, error) {
+    ref, err := s.Source.Now()
+    if err != nil {
+        return 0, err
+    }
+    start := time.Now()
+
+    offset := ref.Sub(s.RTC.ReadTime())
+    threshold := s.Threshold
+    if threshold == 0 {
+        threshold = DefaultSyncThreshold
+    }
+    if offset >= threshold || offset <= -threshold {
+        if err := s.correct(ref, start, offset); err != nil {
+            return offset, err
+        }
+    } else {
+        // Часы сверены без перестановки: отмечаем время RTC, а не эталона.
+        s.RTC.mu.Lock()
+        s.RTC.noteSet(ref.Add(-offset))
+        s.RTC.mu.Unlock()
+    }
+
+    s.mu.Lock()
+    s.lastSync = ref.Truncate(time.Second)
+    s.lastOffset = offset
+    s.synced = true
+    s.mu.Unlock()
+    return offset, nil
+}
+
+// correct исправляет время RTC плавно или перестановкой, выровненной
+// по границе секунды эталона. start - момент получения ref.
+func (s *Syncer) correct(ref, start time.Time, offset time.Duration) error {
+    s.RTC.mu.Lock()
+    defer s.RTC.unlock()
+    if s.Slewer != nil {
+        maxSlew := s.MaxSlew
+        if maxSlew == 0 {
+            maxSlew = DefaultMaxSlew
+        }
+        // Поправка считается от текущего состояния, поэтому
+        // незавершенная прежняя коррекция отбрасывается.
+        s.Slewer.pending = 0
+        if offset < maxSlew && offset > -maxSlew {
+            s.Slewer.Adjust(offset)
+            s.RTC.auditSlew(ref, offset)
+            s.RTC.noteSet(ref.Add(-offset))
+            return nil
+        }
+    }
+    return s.RTC.alignTo(ref.Add(time.Since(start)), AdjustSync, s.Force)
+}
+
+// Due сообщает, пора ли синхронизировать часы: синхронизации еще не было
+// или с последней прошло не меньше Interval по времени RTC.
+func (s *Syncer) Due() bool {
+    last, synced := s.LastSync()
+    if !synced {
+        return true
+    }
+    interval := s.Interval
+    if interval == 0 {
+        interval = DefaultSyncInterval
+    }
+    return s.RTC.ReadTime().Sub(last) >= interval
+}
+
+// SyncIfDue выполняет Sync, если Due возвращает true.
+// Первое значение сообщает, была ли выполнена синхронизация.
+func (s *Syncer) SyncIfDue() (bool, time.Duration, error) {
+    if !s.Due() {
+        return false, 0, nil
+    }
+    offset, err := s.Sync()
+    return err == nil, offset, err
+}
+
+// LastSync возвращает время RTC последней успешной синхронизации.
+func (s *Syncer) LastSync() (time.Time, bool) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.lastSync, s.synced
+}
+
+// LastOffset возвращает расхождение источник минус RTC, измеренное
+// при последней успешной синхронизации (до исправления часов).
+func (s *Syncer) LastOffset() time.Duration {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.lastOffset
+}
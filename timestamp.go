@@ -0,0 +1,39 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// timestampRefresh - наибольшая частота обращения Timestamp к шине.
+const timestampRefresh = time.Second
+
+// Timestamp возвращает время Unix в секундах для пометки измерений
+// в быстрых циклах (1 кГц и выше) без обмена по шине на каждый вызов.
+//
+// Время RTC читается не чаще раза в секунду и запоминается вместе
+// с моментом чтения по монотонным часам МК; между чтениями к нему
+// прибавляются прошедшие целые секунды. Метка может отставать
+// от регистра секунд до одной секунды. Если при обновлении регистры
+// оказываются испорченными, отсчет продолжается от прежнего значения;
+// если не удалось даже первое чтение, возвращается 0.
+func (d *DS1302) Timestamp() uint32 {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    now := time.Now()
+    elapsed := now.Sub(d.tsMono)
+    if d.tsMono.IsZero() || elapsed >= timestampRefresh {
+        t, err := d.readTime()
+        switch {
+        case err == nil:
+            d.tsAnchor = uint32(t.Unix())
+        case d.tsMono.IsZero():
+            // Отсчитывать еще не от чего: следующий вызов повторит чтение.
+            return 0
+        default:
+            d.tsAnchor += uint32(elapsed / time.Second)
+        }
+        d.tsMono = now
+        return d.tsAnchor
+    }
+    return d.tsAnchor + uint32(elapsed/time.Second)
+}
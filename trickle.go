@@ -0,0 +1,68 @@
+package ds1302
+
+// Поля регистра подзарядки: TCS (биты 7-4) включает зарядку значением
+// 1010, DS (биты 3-2) выбирает число диодов, RS (биты 1-0) - резистор.
+const (
+    trickleTCSMask = 0xF0
+    trickleTCSOn   = 0xA0
+    trickleDSMask  = 0x0C
+    trickleRSMask  = 0x03
+)
+
+// TrickleDiodeDrop - падение напряжения на диоде подзарядки по документации, В.
+const TrickleDiodeDrop = 0.7
+
+// TrickleConfig - расшифровка регистра подзарядки.
+type TrickleConfig struct {
+    Raw      uint8  // Сырое значение регистра
+    Enabled  bool   // Подзарядка включена: TCS = 1010 и выбраны диоды и резистор
+    Diodes   uint8  // Число диодов (1, 2; 0 - не выбрано)
+    Resistor uint16 // Сопротивление, Ом (2000, 4000, 8000; 0 - не выбрано)
+}
+
+// decodeTrickle разбирает значение регистра подзарядки.
+func decodeTrickle(v uint8) TrickleConfig {
+    c := TrickleConfig{Raw: v}
+    switch v & trickleDSMask {
+    case 0x04:
+        c.Diodes = 1
+    case 0x08:
+        c.Diodes = 2
+    }
+    switch v & trickleRSMask {
+    case 0x01:
+        c.Resistor = 2000
+    case 0x02:
+        c.Resistor = 4000
+    case 0x03:
+        c.Resistor = 8000
+    }
+    c.Enabled = v&trickleTCSMask == trickleTCSOn && c.Diodes != 0 && c.Resistor != 0
+    return c
+}
+
+// TrickleCharge читает и расшифровывает регистр подзарядки, чтобы
+// проверить, что ионистор или аккумулятор резервного питания заряжается.
+func (d *DS1302) TrickleCharge() (TrickleConfig, error) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if err := d.checkOpen(); err != nil {
+        return TrickleConfig{}, err
+    }
+    return decodeTrickle(d.readRegister(uint8(ClockRead(RegTrickle)))), nil
+}
+
+// ChargeCurrent оценивает максимальный ток зарядки в амперах при напряжении
+// питания vcc (В) и полностью разряженном накопителе:
+// (vcc - Diodes*TrickleDiodeDrop) / Resistor. По мере заряда ток падает.
+// Если подзарядка выключена, возвращает 0.
+func (c TrickleConfig) ChargeCurrent(vcc float64) float64 {
+    if !c.Enabled {
+        return 0
+    }
+    v := vcc - float64(c.Diodes)*TrickleDiodeDrop
+    if v <= 0 {
+        return 0
+    }
+    return v / float64(c.Resistor)
+}
@@ -0,0 +1,100 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// DefaultTrustMaxAge - время после последней установки часов,
+// по истечении которого Trust считает время устаревшим.
+const DefaultTrustMaxAge = 7 * 24 * time.Hour
+
+// TrustLevel - степень доверия к времени RTC.
+type TrustLevel uint8
+
+const (
+    TimeInvalid TrustLevel = iota // Время неверно: генератор стоял, время отклоняет IsTimeValid или часы ушли назад
+    TimeStale                     // Часы идут, но давно (или ни разу) не сверялись
+    TimeTrusted                   // Часы идут и недавно установлены или синхронизированы
+)
+
+// String возвращает название уровня доверия.
+func (l TrustLevel) String() string {
+    switch l {
+    case TimeTrusted:
+        return "trusted"
+    case TimeStale:
+        return "stale"
+    }
+    return "invalid"
+}
+
+// SetTrustMaxAge задает, сколько времени после установки часов Trust
+// возвращает TimeTrusted (0 - DefaultTrustMaxAge).
+func (d *DS1302) SetTrustMaxAge(age time.Duration) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    d.trustMaxAge = age
+}
+
+// Trust сводит состояние часов к одному ответу: можно ли доверять
+// меткам времени RTC или приложению стоит перейти на запасной источник.
+// Время, которое отклоняет IsTimeValid (значение после сброса, выход
+// за окно Config.ValidFrom - Config.ValidUntil), считается неверным.
+//
+// У DS1302 нет флага пропадания питания, поэтому его роль играет бит CH:
+// после полной потери питания генератор обычно остановлен. Замеченная
+// остановка запоминается, и время считается неверным, пока часы не будут
+// установлены заново, даже если генератор уже запущен. Время последней
+// установки хранится в памяти МК; если включен EnableAudit, после
+// перезагрузки оно берется из последней записи журнала.
+func (d *DS1302) Trust() TrustLevel {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if d.checkOpen() != nil {
+        return TimeInvalid
+    }
+    var burst [8]uint8
+    d.readBurst(ClockBurstRead, burst[:])
+    if burst[0]&DS1302_CH_BIT != 0 {
+        d.powerLost = true
+    }
+    if d.powerLost {
+        return TimeInvalid
+    }
+    now, reason := d.validTime(burst)
+    if reason != ReasonOK {
+        return TimeInvalid
+    }
+
+    last := d.lastSet
+    if last.IsZero() && d.audit != nil {
+        if adj, err := d.adjustments(); err == nil && len(adj) > 0 {
+            last = adj[0].At
+        }
+    }
+    if last.IsZero() {
+        return TimeStale
+    }
+
+    age := now.Sub(last.Truncate(time.Second))
+    maxAge := d.trustMaxAge
+    if maxAge == 0 {
+        maxAge = DefaultTrustMaxAge
+    }
+    switch {
+    case age < 0:
+        // Часы показывают время раньше последней установки:
+        // их сбросили или перевели в обход драйвера.
+        return TimeInvalid
+    case age > maxAge:
+        return TimeStale
+    }
+    return TimeTrusted
+}
+
+// noteSet отмечает, что часы установлены или сверены с эталоном на момент t.
+func (d *DS1302) noteSet(t time.Time) {
+    d.lastSet = t
+    d.powerLost = false
+    d.setSeq++
+}
@@ -0,0 +1,75 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// Tx группирует несколько операций записи под одним снятием защиты от записи.
+// Действителен только внутри функции, переданной в Transaction.
+type Tx struct {
+    dev *DS1302
+}
+
+// Transaction снимает защиту от записи один раз, выполняет fn и снова
+// включает защиту - даже если fn вернула ошибку или вызвала панику.
+// Устройство заблокировано на все время fn, поэтому внутри fn работайте
+// с часами только через tx: вызов методов DS1302 приведет к взаимоблокировке.
+//
+//     err := rtc.Transaction(func(tx *ds1302.Tx) error {
+//         if err := tx.SetTime(t); err != nil {
+//             return err
+//         }
+//         if err := tx.WriteRegister(ds1302.ClockWrite(ds1302.RegTrickle), 0xA5); err != nil {
+//             return err
+//         }
+//         return tx.WriteRAMBytes(0, settings)
+//     })
+func (d *DS1302) Transaction(fn func(tx *Tx) error) error {
+    d.mu.Lock()
+    defer d.unlock()
+    if err := d.checkOpen(); err != nil {
+        return err
+    }
+    d.writeRegister(DS1302_WP_WRITE, 0x00)
+    defer d.writeRegister(DS1302_WP_WRITE, DS1302_WP_BIT)
+    
+    return fn(&Tx{dev: d})
+}
+
+// SetTime записывает время в регистры часов.
+// Как и DS1302.SetTime, учитывает GuardBackwards.
+func (tx *Tx) SetTime(t time.Time) error {
+    if err := tx.dev.checkBackwards(t); err != nil {
+        return err
+    }
+    return tx.dev.writeTimeAudited(t, AdjustManual)
+}
+
+// ForceSetTime записывает время в регистры часов без проверки GuardBackwards.
+func (tx *Tx) ForceSetTime(t time.Time) error {
+    return tx.dev.writeTimeAudited(t, AdjustForced)
+}
+
+// WriteRAM записывает байт в резервное ОЗУ по адресу addr (0-30).
+func (tx *Tx) WriteRAM(addr, value uint8) error {
+    return tx.WriteRAMBytes(addr, []byte{value})
+}
+
+// WriteRAMBytes записывает несколько байт в резервное ОЗУ начиная с адреса addr.
+func (tx *Tx) WriteRAMBytes(addr uint8, data []byte) error {
+    if int(addr)+len(data) > RAMSize {
+        return tx.dev.fail(ErrRAMOutOfRange)
+    }
+    tx.dev.writeRAM(addr, data)
+    return nil
+}
+
+// WriteRegister записывает сырое значение в регистр по команде записи cmd,
+// например настройки подзарядки (ClockWrite(RegTrickle)).
+func (tx *Tx) WriteRegister(cmd Command, value uint8) error {
+    if err := cmd.validate(false); err != nil {
+        return tx.dev.fail(err)
+    }
+    tx.dev.writeRegister(uint8(cmd), value)
+    return nil
+}
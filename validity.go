@@ -0,0 +1,76 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// ValidityReason - причина, по которой IsTimeValid отклонила время.
+type ValidityReason uint8
+
+const (
+    ReasonOK           ValidityReason = iota // Время правдоподобно
+    ReasonHalted                             // Генератор остановлен (бит CH)
+    ReasonBadBCD                             // Регистры содержат недопустимые BCD значения
+    ReasonResetDefault                       // Год 2000: значение микросхемы после сброса
+    ReasonOutOfWindow                        // Время вне окна Config.ValidFrom - Config.ValidUntil
+    ReasonUnavailable                        // Устройство закрыто или линии назначены неверно
+)
+
+// String возвращает название причины.
+func (r ValidityReason) String() string {
+    switch r {
+    case ReasonOK:
+        return "ok"
+    case ReasonHalted:
+        return "halted"
+    case ReasonBadBCD:
+        return "bad BCD"
+    case ReasonResetDefault:
+        return "reset default"
+    case ReasonOutOfWindow:
+        return "out of window"
+    case ReasonUnavailable:
+        return "unavailable"
+    }
+    return "unknown"
+}
+
+// IsTimeValid за одну пакетную операцию проверяет, правдоподобно ли время
+// часов: генератор работает, поля декодируются, год отличается от 2000
+// (после сброса DS1302 показывает 01.01.2000) и время попадает в окно
+// Config.ValidFrom - Config.ValidUntil (нулевые границы не проверяются).
+// В отличие от Trust не учитывает, когда часы устанавливались.
+func (d *DS1302) IsTimeValid() (bool, ValidityReason) {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if d.checkOpen() != nil {
+        return false, ReasonUnavailable
+    }
+    var burst [8]uint8
+    d.readBurst(ClockBurstRead, burst[:])
+    _, reason := d.validTime(burst)
+    return reason == ReasonOK, reason
+}
+
+// validTime проверяет снимок регистров часов burst (см. IsTimeValid)
+// и возвращает декодированное время.
+func (d *DS1302) validTime(burst [8]uint8) (time.Time, ValidityReason) {
+    if burst[0]&DS1302_CH_BIT != 0 {
+        return time.Time{}, ReasonHalted
+    }
+    t, err := decodeTime([6]uint8{burst[0], burst[1], burst[2], burst[3], burst[4], burst[6]})
+    if err != nil {
+        d.fail(err)
+        return time.Time{}, ReasonBadBCD
+    }
+    if t.Year() == 2000 {
+        return t, ReasonResetDefault
+    }
+    if from := d.cfg.ValidFrom; !from.IsZero() && t.Before(from) {
+        return t, ReasonOutOfWindow
+    }
+    if until := d.cfg.ValidUntil; !until.IsZero() && t.After(until) {
+        return t, ReasonOutOfWindow
+    }
+    return t, ReasonOK
+}
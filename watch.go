@@ -0,0 +1,97 @@
+package ds1302
+
+import (
+    "time"
+)
+
+// DefaultWatchInterval - период опроса Watcher.Run по умолчанию.
+const DefaultWatchInterval = 100 * time.Millisecond
+
+// WatchConfig задает обработчики смены секунды, минуты и часа.
+// Незаданные обработчики не вызываются.
+type WatchConfig struct {
+    OnSecond func(DateTime) // Смена секунды
+    OnMinute func(DateTime) // Смена минуты
+    OnHour   func(DateTime) // Смена часа
+
+    Interval time.Duration // Период опроса в Run (0 - DefaultWatchInterval)
+}
+
+// Watcher опрашивает часы и вызывает обработчики при смене
+// соответствующего регистра, избавляя прошивку циферблата
+// от собственных циклов "сравнить с прошлым значением".
+type Watcher struct {
+    dev     *DS1302
+    cfg     WatchConfig
+    last    DateTime
+    started bool
+}
+
+// Watch создает Watcher с обработчиками из cfg.
+//
+//     w := rtc.Watch(ds1302.WatchConfig{
+//         OnMinute: func(dt ds1302.DateTime) { redraw(dt) },
+//     })
+//     for {
+//         w.Poll()
+//         ...
+//     }
+func (d *DS1302) Watch(cfg WatchConfig) *Watcher {
+    return &Watcher{dev: d, cfg: cfg}
+}
+
+// Poll один раз читает часы и вызывает обработчики, если с прошлого
+// опроса сменилась секунда, минута или час (в этом порядке). Первый вызов
+// только запоминает текущее время. Для обнаружения каждой секунды
+// вызывайте Poll чаще одного раза в секунду. Ошибка чтения возвращается
+// без вызова обработчиков; следующий опрос сравнит время с последним
+// успешно прочитанным.
+func (w *Watcher) Poll() error {
+    dt, err := w.dev.ReadDateTime()
+    if err != nil {
+        return err
+    }
+    if !w.started {
+        w.last = dt
+        w.started = true
+        return nil
+    }
+    
+    prev := w.last
+    w.last = dt
+    if dt == prev {
+        return nil
+    }
+    
+    if w.cfg.OnSecond != nil {
+        w.cfg.OnSecond(dt)
+    }
+    if dt.Minute != prev.Minute || dt.Hour != prev.Hour || dt.Day != prev.Day {
+        if w.cfg.OnMinute != nil {
+            w.cfg.OnMinute(dt)
+        }
+        if dt.Hour != prev.Hour || dt.Day != prev.Day {
+            if w.cfg.OnHour != nil {
+                w.cfg.OnHour(dt)
+            }
+        }
+    }
+    return nil
+}
+
+// Run вызывает Poll с периодом cfg.Interval, пока не будет закрыт stop.
+// Предназначен для запуска в отдельной горутине.
+func (w *Watcher) Run(stop <-chan struct{}) {
+    interval := w.cfg.Interval
+    if interval == 0 {
+        interval = DefaultWatchInterval
+    }
+    for {
+        w.Poll()
+        select {
+        case <-stop:
+            return
+        case <-time.After(interval):
+        }
+    }
+}
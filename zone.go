@@ -0,0 +1,33 @@
+package ds1302
+
+import (
+    "errors"
+)
+
+// ErrUnknownZone возвращается, если часовой пояс не встроен в прошивку:
+// сборка без тега ds1302tz или имя отсутствует в таблице (см. Zones).
+var ErrUnknownZone = errors.New("ds1302: time zone is not embedded (build with -tags ds1302tz)")
+
+// zoneMagic отличает номер пояса в ОЗУ от смещения (offsetMagic).
+const zoneMagic = 0xA5
+
+// SetLocalZone сохраняет в ОЗУ часовой пояс вместо постоянного смещения,
+// чтобы ReadTimeLocal учитывала переход на летнее время. Пояса встраиваются
+// в прошивку тегом сборки ds1302tz (см. Zones); без него прошивка остается
+// маленькой и работает только с SetLocalOffset.
+//
+// Встроены правила, действующие сейчас (формат POSIX TZ), без истории
+// прежних переходов: для времени до последней смены правил пояса
+// результат может отличаться от полной базы tzdata.
+func (d *DS1302) SetLocalZone(name string) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+    if !d.localEnabled {
+        return d.fail(ErrLocalOffsetDisabled)
+    }
+    idx, ok := zoneIndex(name)
+    if !ok {
+        return d.fail(ErrUnknownZone)
+    }
+    return d.writeRAMBytes(d.localAddr, []byte{idx, idx ^ zoneMagic})
+}
@@ -0,0 +1,20 @@
+//go:build !ds1302tz
+
+package ds1302
+
+import (
+    "time"
+)
+
+// Zones возвращает имена встроенных часовых поясов; без тега ds1302tz их нет.
+func Zones() []string {
+    return nil
+}
+
+func zoneIndex(name string) (uint8, bool) {
+    return 0, false
+}
+
+func zoneLocation(idx uint8) (*time.Location, bool) {
+    return nil, false
+}